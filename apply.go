@@ -0,0 +1,87 @@
+package devbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// ApplyResult lists what ApplyDevboxList did for each devbox.
+type ApplyResult struct {
+	Created   []string
+	Updated   []string
+	Unchanged []string
+	Errors    []BatchResult
+}
+
+// ApplyDevboxList reads DevboxConfig items from a YAML or JSON file and calls
+// EnsureDevbox for each one. Each document may hold a single item or a list of
+// items, and documents are separated by "---". A failure for one item is
+// recorded in Errors and does not stop the others.
+func (s *DevboxSDK) ApplyDevboxList(ctx context.Context, path string) (ApplyResult, error) {
+	var result ApplyResult
+
+	configs, err := readDevboxConfigs(path)
+	if err != nil {
+		return result, err
+	}
+
+	for _, cfg := range configs {
+		_, outcome, err := s.ensureDevbox(ctx, cfg)
+		switch {
+		case err != nil:
+			result.Errors = append(result.Errors, BatchResult{Name: cfg.Name, Err: err})
+		case outcome == ensureCreated:
+			result.Created = append(result.Created, cfg.Name)
+		case outcome == ensureUpdated:
+			result.Updated = append(result.Updated, cfg.Name)
+		default:
+			result.Unchanged = append(result.Unchanged, cfg.Name)
+		}
+	}
+	return result, nil
+}
+
+// readDevboxConfigs decodes all DevboxConfig items from a multi-document file.
+func readDevboxConfigs(path string) ([]DevboxConfig, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var configs []DevboxConfig
+	decoder := yaml.NewYAMLOrJSONDecoder(f, 4096)
+	for {
+		var doc json.RawMessage
+		if err := decoder.Decode(&doc); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		doc = bytes.TrimSpace(doc)
+		switch {
+		case len(doc) == 0 || bytes.Equal(doc, []byte("null")):
+			continue
+		case doc[0] == '[':
+			var items []DevboxConfig
+			if err := json.Unmarshal(doc, &items); err != nil {
+				return nil, err
+			}
+			configs = append(configs, items...)
+		default:
+			var item DevboxConfig
+			if err := json.Unmarshal(doc, &item); err != nil {
+				return nil, err
+			}
+			configs = append(configs, item)
+		}
+	}
+	return configs, nil
+}
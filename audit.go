@@ -0,0 +1,148 @@
+package devbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+
+	"github.com/gitlayzer/devbox-sdk-go/api/v1alpha2"
+)
+
+// auditEventReason marks the Kubernetes Events that carry audit entries.
+const auditEventReason = "DevboxSDKAudit"
+
+// AuditEntry is a single record written to the SDK audit log.
+type AuditEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Method    string    `json:"method"`
+	User      string    `json:"user,omitempty"`
+	Outcome   string    `json:"outcome"`
+	Details   string    `json:"details,omitempty"`
+}
+
+// GetAuditTrail returns the audit entries recorded for the devbox since the
+// given time, oldest first. Entries are recorded as Kubernetes Events on the
+// devbox by SDKs configured with WithAuditLog, so they are subject to the
+// cluster's event TTL.
+func (d *Devbox) GetAuditTrail(ctx context.Context, since time.Time) ([]AuditEntry, error) {
+	selector := fields.Set{
+		"involvedObject.kind": "Devbox",
+		"involvedObject.name": d.crd.Name,
+		"reason":              auditEventReason,
+	}.AsSelector().String()
+
+	events, err := d.sdk.client.Clientset().CoreV1().Events(d.crd.Namespace).List(ctx, metav1.ListOptions{FieldSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []AuditEntry
+	for _, event := range events.Items {
+		var entry AuditEntry
+		if err := json.Unmarshal([]byte(event.Message), &entry); err != nil {
+			continue
+		}
+		if entry.Timestamp.Before(since) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+	return entries, nil
+}
+
+// audit writes an entry to the audit log configured with WithAuditLog, if any.
+func (s *DevboxSDK) audit(method, details string, err error) {
+	if s.auditLog == nil {
+		return
+	}
+	s.writeAudit(s.auditEntry(method, details, err))
+}
+
+// audit writes an entry to the SDK audit log and records it as an Event on
+// the devbox, so it can be read back with GetAuditTrail. Failing to record
+// the Event does not affect the audited operation.
+func (d *Devbox) audit(ctx context.Context, method, details string, err error) {
+	if d.sdk.auditLog == nil {
+		return
+	}
+	entry := d.sdk.auditEntry(method, details, err)
+	line := d.sdk.writeAudit(entry)
+	if line == nil {
+		return
+	}
+
+	now := metav1.NewTime(entry.Timestamp)
+	eventType := corev1.EventTypeNormal
+	if err != nil {
+		eventType = corev1.EventTypeWarning
+	}
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s.%x", d.crd.Name, entry.Timestamp.UnixNano()),
+			Namespace: d.crd.Namespace,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion:      v1alpha2.GroupVersion.String(),
+			Kind:            "Devbox",
+			Name:            d.crd.Name,
+			Namespace:       d.crd.Namespace,
+			UID:             d.crd.UID,
+			ResourceVersion: d.crd.ResourceVersion,
+		},
+		Reason:         auditEventReason,
+		Message:        string(line),
+		Type:           eventType,
+		Source:         corev1.EventSource{Component: "devbox-sdk"},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+	}
+	d.sdk.client.Clientset().CoreV1().Events(d.crd.Namespace).Create(ctx, event, metav1.CreateOptions{})
+}
+
+func (s *DevboxSDK) auditEntry(method, details string, err error) AuditEntry {
+	entry := AuditEntry{
+		Timestamp: time.Now().UTC(),
+		Method:    method,
+		User:      s.auditUser(),
+		Outcome:   "success",
+		Details:   details,
+	}
+	if err != nil {
+		entry.Outcome = "error: " + err.Error()
+	}
+	return entry
+}
+
+// writeAudit writes the entry as a JSON line and returns the encoded entry,
+// or nil if it could not be encoded.
+func (s *DevboxSDK) writeAudit(entry AuditEntry) []byte {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return nil
+	}
+	s.auditLog.Write(append(line, '\n'))
+	return line
+}
+
+// auditUser returns the identity the SDK acts as, as far as the client
+// configuration reveals it. Token-based configs yield an empty user.
+func (s *DevboxSDK) auditUser() string {
+	switch {
+	case s.restConfig == nil:
+		return ""
+	case s.restConfig.Impersonate.UserName != "":
+		return s.restConfig.Impersonate.UserName
+	default:
+		return s.restConfig.Username
+	}
+}
@@ -0,0 +1,189 @@
+// Package autopause pauses and shuts down idle devboxes to save cost.
+//
+// A Controller periodically asks an IdleDetector whether each running devbox
+// is in use and applies a Policy to those that are not:
+//
+//	c := &autopause.Controller{
+//		SDK:      sdk,
+//		Detector: autopause.AnyActive(autopause.SSHDetector{}, autopause.CPUDetector{Threshold: 0.1}),
+//		Policy:   autopause.Policy{PauseAfter: 30 * time.Minute, ShutdownAfter: 24 * time.Hour},
+//	}
+//	err := c.Run(ctx)
+//
+// Each devbox has two idle clocks, tracked in memory from when the controller
+// first sees it, so restarting the controller restarts every clock. The pause
+// clock restarts whenever the devbox is active or resumed from a pause; the
+// shutdown clock restarts only when it is active, so that a devbox that keeps
+// being resumed without being used is still shut down eventually.
+package autopause
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	devbox "github.com/gitlayzer/devbox-sdk-go"
+	"github.com/gitlayzer/devbox-sdk-go/api/v1alpha2"
+)
+
+// defaultInterval is how often a Controller checks the devboxes when its
+// Interval is unset.
+const defaultInterval = time.Minute
+
+// Policy says when idle devboxes are paused and shut down. A zero duration
+// disables the corresponding action.
+type Policy struct {
+	// PauseAfter is how long a running devbox may be idle, counted from its
+	// last activity or resume, before it is paused.
+	PauseAfter time.Duration
+	// ShutdownAfter is how long a running or paused devbox may go without
+	// activity before it is shut down, releasing its storage-backed
+	// resources too.
+	ShutdownAfter time.Duration
+	// ListOptions selects the devboxes the policy applies to. Its Phases are
+	// ignored.
+	ListOptions devbox.ListOptions
+}
+
+// Action is what a Controller did to a devbox.
+type Action string
+
+const (
+	ActionPause    Action = "Pause"
+	ActionShutdown Action = "Shutdown"
+	// ActionDetect reports a failure of the idle detector, in which case the
+	// devbox counts as active.
+	ActionDetect Action = "Detect"
+)
+
+// Controller applies a Policy to the devboxes in the namespace of an SDK.
+type Controller struct {
+	SDK      *devbox.DevboxSDK
+	Detector IdleDetector
+	Policy   Policy
+	// Interval is the time between checks. Zero means one minute.
+	Interval time.Duration
+	// OnAction, if set, is called after every action taken on a devbox and
+	// every detector failure, with the error, if any.
+	OnAction func(name string, action Action, err error)
+
+	mu     sync.Mutex
+	clocks map[string]*idleClock
+}
+
+// idleClock tracks the idleness of one devbox.
+type idleClock struct {
+	// phase is the phase the devbox had at the previous check.
+	phase v1alpha2.DevboxPhase
+	// pauseSince is when the devbox was last active or resumed.
+	pauseSince time.Time
+	// shutdownSince is when the devbox was last active.
+	shutdownSince time.Time
+}
+
+// Run checks the devboxes every Interval until ctx is cancelled. It returns
+// early only if the controller is misconfigured; failures to list devboxes
+// are retried at the next check.
+func (c *Controller) Run(ctx context.Context) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+	interval := c.Interval
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		c.Reconcile(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Reconcile checks every running or paused devbox once, pausing or shutting
+// down those idle for longer than the policy allows.
+func (c *Controller) Reconcile(ctx context.Context) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+	opts := c.Policy.ListOptions
+	opts.Phases = []v1alpha2.DevboxPhase{v1alpha2.DevboxPhaseRunning, v1alpha2.DevboxPhasePaused}
+	devboxes, err := c.SDK.ListDevboxes(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.clocks == nil {
+		c.clocks = map[string]*idleClock{}
+	}
+
+	now := time.Now()
+	seen := make(map[string]bool, len(devboxes))
+	for _, d := range devboxes {
+		seen[d.Name()] = true
+		phase := v1alpha2.DevboxPhase(d.Status())
+		clock, ok := c.clocks[d.Name()]
+		if !ok {
+			clock = &idleClock{phase: phase, pauseSince: now, shutdownSince: now}
+			c.clocks[d.Name()] = clock
+		}
+		running := phase == v1alpha2.DevboxPhaseRunning
+		if running && clock.phase != v1alpha2.DevboxPhaseRunning {
+			// Someone resumed the devbox; give them a full PauseAfter.
+			clock.pauseSince = now
+		}
+		clock.phase = phase
+
+		if running {
+			active, err := c.Detector.Active(ctx, d)
+			if err != nil {
+				c.report(d.Name(), ActionDetect, err)
+				active = true
+			}
+			if active {
+				clock.pauseSince = now
+				clock.shutdownSince = now
+				continue
+			}
+		}
+
+		switch {
+		case c.Policy.ShutdownAfter > 0 && now.Sub(clock.shutdownSince) >= c.Policy.ShutdownAfter:
+			c.report(d.Name(), ActionShutdown, d.Shutdown(ctx))
+		case running && c.Policy.PauseAfter > 0 && now.Sub(clock.pauseSince) >= c.Policy.PauseAfter:
+			err := d.Pause(ctx)
+			if err == nil {
+				clock.phase = v1alpha2.DevboxPhasePaused
+			}
+			c.report(d.Name(), ActionPause, err)
+		}
+	}
+
+	// Forget devboxes that were deleted, stopped or shut down.
+	for name := range c.clocks {
+		if !seen[name] {
+			delete(c.clocks, name)
+		}
+	}
+	return nil
+}
+
+func (c *Controller) validate() error {
+	if c.SDK == nil || c.Detector == nil {
+		return errors.New("autopause: controller needs an SDK and a detector")
+	}
+	return nil
+}
+
+func (c *Controller) report(name string, action Action, err error) {
+	if c.OnAction != nil {
+		c.OnAction(name, action, err)
+	}
+}
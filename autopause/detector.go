@@ -0,0 +1,80 @@
+package autopause
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	devbox "github.com/gitlayzer/devbox-sdk-go"
+)
+
+// IdleDetector decides whether a running devbox is in use.
+type IdleDetector interface {
+	Active(ctx context.Context, d *devbox.Devbox) (bool, error)
+}
+
+// IdleDetectorFunc adapts a function to IdleDetector.
+type IdleDetectorFunc func(ctx context.Context, d *devbox.Devbox) (bool, error)
+
+// Active calls f.
+func (f IdleDetectorFunc) Active(ctx context.Context, d *devbox.Devbox) (bool, error) {
+	return f(ctx, d)
+}
+
+// AnyActive returns a detector that reports a devbox as active if any of
+// detectors does. Detectors are asked in order until one reports activity;
+// an error stops the evaluation.
+func AnyActive(detectors ...IdleDetector) IdleDetector {
+	return IdleDetectorFunc(func(ctx context.Context, d *devbox.Devbox) (bool, error) {
+		for _, detector := range detectors {
+			active, err := detector.Active(ctx, d)
+			if err != nil || active {
+				return active, err
+			}
+		}
+		return false, nil
+	})
+}
+
+// sshConnectionsCommand counts the established TCP connections to port 22
+// (0016 in hex) in /proc/net/tcp and tcp6, where the state 01 is ESTABLISHED.
+const sshConnectionsCommand = `cat /proc/net/tcp /proc/net/tcp6 2>/dev/null | awk '$2 ~ /:0016$/ && $4 == "01"' | wc -l`
+
+// SSHDetector reports a devbox as active while it has SSH connections other
+// than the one the detector itself opens, such as terminals or remote IDE
+// sessions.
+type SSHDetector struct{}
+
+// Active counts the SSH connections of the devbox.
+func (SSHDetector) Active(ctx context.Context, d *devbox.Devbox) (bool, error) {
+	result, err := d.Exec(ctx, sshConnectionsCommand, devbox.ExecOptions{})
+	if err != nil {
+		return false, err
+	}
+	if result.ExitCode != 0 {
+		return false, fmt.Errorf("counting SSH connections: exit code %d: %s", result.ExitCode, strings.TrimSpace(result.Stderr))
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(result.Stdout))
+	if err != nil {
+		return false, fmt.Errorf("counting SSH connections: %w", err)
+	}
+	// One of the connections is the detector's own.
+	return n > 1, nil
+}
+
+// CPUDetector reports a devbox as active while its CPU usage, as reported by
+// the metrics API, is at least Threshold cores. The metrics API averages
+// usage over a short window, so brief bursts may be missed.
+type CPUDetector struct {
+	Threshold float64
+}
+
+// Active compares the CPU usage of the devbox with the threshold.
+func (c CPUDetector) Active(ctx context.Context, d *devbox.Devbox) (bool, error) {
+	cpu, _, err := d.GetResourceUsage(ctx)
+	if err != nil {
+		return false, err
+	}
+	return cpu >= c.Threshold, nil
+}
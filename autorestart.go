@@ -0,0 +1,72 @@
+package devbox
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+const (
+	annotationAutoRestart          = "devbox.sealos.run/auto-restart"
+	annotationMaxRestarts          = "devbox.sealos.run/max-restarts"
+	annotationRestartWindowSeconds = "devbox.sealos.run/restart-window-seconds"
+)
+
+// AutoRestartPolicy tells the operator to restart a crashed devbox at most
+// MaxRestarts times within a sliding window of WindowSeconds.
+type AutoRestartPolicy struct {
+	Enabled       bool
+	MaxRestarts   int32
+	WindowSeconds int64
+}
+
+// EnableAutoRestart lets the operator restart the devbox when its container
+// crashes, up to maxRestarts times within windowSeconds.
+func (d *Devbox) EnableAutoRestart(ctx context.Context, maxRestarts int32, windowSeconds int64) error {
+	if maxRestarts <= 0 || windowSeconds <= 0 {
+		return fmt.Errorf("max restarts and restart window must be positive, got %d and %d", maxRestarts, windowSeconds)
+	}
+	return d.patchAnnotations(ctx, map[string]interface{}{
+		annotationAutoRestart:          "true",
+		annotationMaxRestarts:          strconv.FormatInt(int64(maxRestarts), 10),
+		annotationRestartWindowSeconds: strconv.FormatInt(windowSeconds, 10),
+	})
+}
+
+// DisableAutoRestart removes the auto-restart policy from the devbox.
+func (d *Devbox) DisableAutoRestart(ctx context.Context) error {
+	return d.patchAnnotations(ctx, map[string]interface{}{
+		annotationAutoRestart:          nil,
+		annotationMaxRestarts:          nil,
+		annotationRestartWindowSeconds: nil,
+	})
+}
+
+// GetAutoRestartPolicy returns the current auto-restart policy of the devbox.
+// Enabled is false if no policy is set.
+func (d *Devbox) GetAutoRestartPolicy(ctx context.Context) (*AutoRestartPolicy, error) {
+	if err := d.RefreshInfo(ctx); err != nil {
+		return nil, err
+	}
+
+	annotations := d.crd.Annotations
+	policy := &AutoRestartPolicy{Enabled: annotations[annotationAutoRestart] == "true"}
+	if !policy.Enabled {
+		return policy, nil
+	}
+	if raw := annotations[annotationMaxRestarts]; raw != "" {
+		n, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s annotation %q: %w", annotationMaxRestarts, raw, err)
+		}
+		policy.MaxRestarts = int32(n)
+	}
+	if raw := annotations[annotationRestartWindowSeconds]; raw != "" {
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s annotation %q: %w", annotationRestartWindowSeconds, raw, err)
+		}
+		policy.WindowSeconds = n
+	}
+	return policy, nil
+}
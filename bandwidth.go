@@ -0,0 +1,50 @@
+package devbox
+
+import (
+	"context"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	annotationBandwidthIngress = "devbox.sealos.run/bandwidth-ingress-mbps"
+	annotationBandwidthEgress  = "devbox.sealos.run/bandwidth-egress-mbps"
+
+	// annotationBandwidthSupported is set on namespaces whose network plugin
+	// enforces the bandwidth annotations.
+	annotationBandwidthSupported = "devbox.sealos.run/bandwidth-limit-supported"
+)
+
+// GetBandwidthLimit returns the ingress and egress bandwidth limits in Mbps.
+// Zero means unlimited.
+func (d *Devbox) GetBandwidthLimit() (ingressMbps, egressMbps float64) {
+	ingressMbps, _ = strconv.ParseFloat(d.crd.Annotations[annotationBandwidthIngress], 64)
+	egressMbps, _ = strconv.ParseFloat(d.crd.Annotations[annotationBandwidthEgress], 64)
+	return ingressMbps, egressMbps
+}
+
+// SetBandwidthLimit sets the ingress and egress bandwidth limits in Mbps. Zero
+// removes the corresponding limit.
+func (d *Devbox) SetBandwidthLimit(ctx context.Context, ingressMbps, egressMbps float64) error {
+	ns, err := d.sdk.client.Clientset().CoreV1().Namespaces().Get(ctx, d.crd.Namespace, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if ns.Annotations[annotationBandwidthSupported] != "true" {
+		return ErrBandwidthLimitNotSupported
+	}
+
+	return d.patchAnnotations(ctx, map[string]interface{}{
+		annotationBandwidthIngress: mbpsAnnotation(ingressMbps),
+		annotationBandwidthEgress:  mbpsAnnotation(egressMbps),
+	})
+}
+
+// mbpsAnnotation formats a bandwidth limit, returning nil for no limit.
+func mbpsAnnotation(mbps float64) interface{} {
+	if mbps <= 0 {
+		return nil
+	}
+	return strconv.FormatFloat(mbps, 'f', -1, 64)
+}
@@ -0,0 +1,124 @@
+package devbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/gitlayzer/devbox-sdk-go/api/v1alpha2"
+)
+
+// defaultBatchConcurrency is how many devboxes a batch operation works on at
+// once when BatchOptions leaves Concurrency unset.
+const defaultBatchConcurrency = 10
+
+// BatchResult is the outcome of an operation on a single devbox within a batch.
+type BatchResult struct {
+	Name string
+	Err  error
+}
+
+// BatchOptions configures the batch operations of the SDK.
+type BatchOptions struct {
+	// Concurrency is the number of devboxes worked on at once. Zero means 10.
+	Concurrency int
+}
+
+// BatchResults holds one BatchResult per devbox of a batch operation, in the
+// order the names were given.
+type BatchResults []BatchResult
+
+// Failed returns the results whose operation failed.
+func (r BatchResults) Failed() []BatchResult {
+	var failed []BatchResult
+	for _, res := range r {
+		if res.Err != nil {
+			failed = append(failed, res)
+		}
+	}
+	return failed
+}
+
+// Err joins the errors of the failed operations, each prefixed with the
+// devbox name, or returns nil if all succeeded.
+func (r BatchResults) Err() error {
+	var errs []error
+	for _, res := range r.Failed() {
+		errs = append(errs, fmt.Errorf("devbox %s: %w", res.Name, res.Err))
+	}
+	return errors.Join(errs...)
+}
+
+// BatchStart starts the named devboxes concurrently. A failure on one devbox
+// is recorded in its result and does not stop the others.
+func (s *DevboxSDK) BatchStart(ctx context.Context, names []string, opts BatchOptions) BatchResults {
+	return s.batchUpdateState(ctx, names, opts, v1alpha2.DevboxStateRunning)
+}
+
+// BatchStop stops the named devboxes concurrently. A failure on one devbox is
+// recorded in its result and does not stop the others.
+func (s *DevboxSDK) BatchStop(ctx context.Context, names []string, opts BatchOptions) BatchResults {
+	return s.batchUpdateState(ctx, names, opts, v1alpha2.DevboxStateStopped)
+}
+
+// BatchPause pauses the named devboxes concurrently. A failure on one devbox
+// is recorded in its result and does not stop the others.
+func (s *DevboxSDK) BatchPause(ctx context.Context, names []string, opts BatchOptions) BatchResults {
+	return s.batchUpdateState(ctx, names, opts, v1alpha2.DevboxStatePaused)
+}
+
+// BatchShutdown shuts down the named devboxes concurrently. A failure on one
+// devbox is recorded in its result and does not stop the others.
+func (s *DevboxSDK) BatchShutdown(ctx context.Context, names []string, opts BatchOptions) BatchResults {
+	return s.batchUpdateState(ctx, names, opts, v1alpha2.DevboxStateShutdown)
+}
+
+// BatchDelete deletes the named devboxes concurrently. A failure on one
+// devbox, including ErrDevboxLocked for a locked one, is recorded in its
+// result and does not stop the others.
+func (s *DevboxSDK) BatchDelete(ctx context.Context, names []string, opts BatchOptions) BatchResults {
+	return s.runBatch(ctx, names, opts, s.deleteDevbox)
+}
+
+func (s *DevboxSDK) batchUpdateState(ctx context.Context, names []string, opts BatchOptions, state v1alpha2.DevboxState) BatchResults {
+	return s.runBatch(ctx, names, opts, func(ctx context.Context, name string) error {
+		err := apiError(s.client.UpdateState(ctx, name, state))
+		s.logState(ctx, s.namespace, name, state, err)
+		s.metrics.recordTransition(state, err)
+		return err
+	})
+}
+
+// runBatch calls op for every name from a pool of opts.Concurrency workers.
+// Names not yet started when ctx is cancelled fail with the context error.
+func (s *DevboxSDK) runBatch(ctx context.Context, names []string, opts BatchOptions, op func(context.Context, string) error) BatchResults {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+
+	results := make(BatchResults, len(names))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < min(concurrency, len(names)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = BatchResult{Name: names[i]}
+				if err := ctx.Err(); err != nil {
+					results[i].Err = err
+					continue
+				}
+				results[i].Err = op(ctx, names[i])
+			}
+		}()
+	}
+	for i := range names {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,88 @@
+package devbox
+
+import (
+	"context"
+	"encoding/json"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gitlayzer/devbox-sdk-go/api/v1alpha2"
+)
+
+const annotationBookmarks = "devbox.sealos.run/bookmarks"
+
+// Bookmark adds label to the bookmarks of the devbox. Adding an existing
+// bookmark is a no-op.
+func (d *Devbox) Bookmark(ctx context.Context, label string) error {
+	if err := d.RefreshInfo(ctx); err != nil {
+		return err
+	}
+
+	bookmarks := d.ListBookmarks()
+	for _, b := range bookmarks {
+		if b == label {
+			return nil
+		}
+	}
+	return d.setBookmarks(ctx, append(bookmarks, label))
+}
+
+// RemoveBookmark removes label from the bookmarks of the devbox.
+func (d *Devbox) RemoveBookmark(ctx context.Context, label string) error {
+	if err := d.RefreshInfo(ctx); err != nil {
+		return err
+	}
+
+	var bookmarks []string
+	for _, b := range d.ListBookmarks() {
+		if b != label {
+			bookmarks = append(bookmarks, b)
+		}
+	}
+	return d.setBookmarks(ctx, bookmarks)
+}
+
+// ListBookmarks returns the bookmarks of the devbox from the cached CRD.
+func (d *Devbox) ListBookmarks() []string {
+	return bookmarks(d.crd)
+}
+
+// FindBookmarked returns the devboxes bookmarked with label.
+func (s *DevboxSDK) FindBookmarked(ctx context.Context, label string) ([]*Devbox, error) {
+	list, err := s.client.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return s.wrapList(list, func(d *v1alpha2.Devbox) bool {
+		for _, b := range bookmarks(d) {
+			if b == label {
+				return true
+			}
+		}
+		return false
+	}), nil
+}
+
+func (d *Devbox) setBookmarks(ctx context.Context, bookmarks []string) error {
+	if len(bookmarks) == 0 {
+		return d.patchAnnotations(ctx, map[string]interface{}{annotationBookmarks: nil})
+	}
+	data, err := json.Marshal(bookmarks)
+	if err != nil {
+		return err
+	}
+	return d.patchAnnotations(ctx, map[string]interface{}{annotationBookmarks: string(data)})
+}
+
+// bookmarks decodes the bookmark annotation, ignoring malformed values.
+func bookmarks(d *v1alpha2.Devbox) []string {
+	raw, ok := d.Annotations[annotationBookmarks]
+	if !ok {
+		return nil
+	}
+	var labels []string
+	if err := json.Unmarshal([]byte(raw), &labels); err != nil {
+		return nil
+	}
+	return labels
+}
@@ -0,0 +1,149 @@
+package devbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RegistryAuth contains credentials for pushing to an image registry.
+type RegistryAuth struct {
+	Server   string
+	Username string
+	Password string
+}
+
+// BuildConfig contains configuration for building an image inside a devbox.
+type BuildConfig struct {
+	// Dockerfile is the path of the Dockerfile relative to the build context.
+	// Defaults to "Dockerfile".
+	Dockerfile string
+	BuildArgs  map[string]string
+	// Tag is the full image reference to build and push, e.g.
+	// "registry.example.com/team/app:v1".
+	Tag          string
+	RegistryAuth RegistryAuth
+}
+
+// buildCleanupTimeout bounds the removal of the build context from the devbox
+// once a build is over.
+const buildCleanupTimeout = 30 * time.Second
+
+// BuildAndPush uploads contextDir to the devbox, builds an image from it with
+// docker, pushes the image and creates a release of the devbox for it. The
+// release is versioned after the tag of cfg.Tag and records the pushed image
+// by digest, e.g. "registry.example.com/team/app@sha256:...", so that it
+// keeps pointing at this build when the tag is pushed again.
+func (d *Devbox) BuildAndPush(ctx context.Context, contextDir string, cfg BuildConfig) (*Release, error) {
+	if cfg.Tag == "" {
+		return nil, errors.New("build tag is required")
+	}
+	dockerfile := cfg.Dockerfile
+	if dockerfile == "" {
+		dockerfile = "Dockerfile"
+	}
+
+	// Stream the tarball straight into the upload
+	remoteDir := "/tmp/devbox-build-" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeTarball(contextDir, pw))
+	}()
+	if err := d.UploadTarball(ctx, pr, remoteDir); err != nil {
+		pr.CloseWithError(err)
+		return nil, err
+	}
+	defer func() {
+		// Clean up even if ctx is done, but do not hang on a dead devbox.
+		cleanupCtx, cancel := context.WithTimeout(context.Background(), buildCleanupTimeout)
+		defer cancel()
+		d.runSSH(cleanupCtx, "rm -rf "+shellQuote(remoteDir), nil)
+	}()
+
+	if cfg.RegistryAuth.Username != "" {
+		login := "docker login --username " + shellQuote(cfg.RegistryAuth.Username) + " --password-stdin"
+		if cfg.RegistryAuth.Server != "" {
+			login += " " + shellQuote(cfg.RegistryAuth.Server)
+		}
+		if _, err := d.runSSH(ctx, login, strings.NewReader(cfg.RegistryAuth.Password)); err != nil {
+			return nil, err
+		}
+	}
+
+	build := []string{"docker", "build", "-t", shellQuote(cfg.Tag), "-f", shellQuote(dockerfile)}
+	args := make([]string, 0, len(cfg.BuildArgs))
+	for k := range cfg.BuildArgs {
+		args = append(args, k)
+	}
+	sort.Strings(args)
+	for _, k := range args {
+		build = append(build, "--build-arg", shellQuote(k+"="+cfg.BuildArgs[k]))
+	}
+	build = append(build, ".")
+
+	if _, err := d.runSSH(ctx, "cd "+shellQuote(remoteDir)+" && "+strings.Join(build, " "), nil); err != nil {
+		return nil, err
+	}
+	if _, err := d.runSSH(ctx, "docker push "+shellQuote(cfg.Tag), nil); err != nil {
+		return nil, err
+	}
+
+	// The push records the digest the registry assigned among the repo
+	// digests of the local image.
+	out, err := d.runSSH(ctx, "docker image inspect --format '{{join .RepoDigests \"\\n\"}}' "+shellQuote(cfg.Tag), nil)
+	if err != nil {
+		return nil, err
+	}
+	digest := repoDigest(string(out), cfg.Tag)
+	if digest == "" {
+		return nil, fmt.Errorf("no repository digest for pushed image %s", cfg.Tag)
+	}
+	return d.CreateRelease(ctx, ReleaseConfig{Version: releaseVersion(cfg.Tag), Image: digest})
+}
+
+// releaseVersion derives a release version, which is part of the release
+// name, from the tag of an image reference: lowercased, with underscores,
+// which object names cannot contain, turned into dashes. A reference without
+// a tag yields "latest".
+func releaseVersion(ref string) string {
+	tag := "latest"
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		tag = ref[i+1:]
+	}
+	version := strings.Trim(strings.ReplaceAll(strings.ToLower(tag), "_", "-"), "-.")
+	if version == "" {
+		return "latest"
+	}
+	return version
+}
+
+// repoDigest picks the digest of the repository of ref from the repo digests
+// listed by docker image inspect, one per line. Docker may list the
+// repository under a shorter name than ref, such as "team/app" for
+// "docker.io/team/app", so a single digest is taken as is. It returns "" if
+// there is no match.
+func repoDigest(digests, ref string) string {
+	repo := ref
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		repo = ref[:i]
+	}
+	var all []string
+	for _, line := range strings.Split(digests, "\n") {
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+		if name, _, ok := strings.Cut(line, "@"); ok && name == repo {
+			return line
+		}
+		all = append(all, line)
+	}
+	if len(all) == 1 {
+		return all[0]
+	}
+	return ""
+}
@@ -0,0 +1,120 @@
+package devbox
+
+import (
+	"context"
+	"errors"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/gitlayzer/devbox-sdk-go/api/v1alpha2"
+)
+
+// DevboxBuilder assembles a DevboxConfig step by step and creates the devbox.
+// Its methods return the builder so that calls can be chained:
+//
+//	d, err := devbox.NewBuilder(sdk, "my-devbox").
+//		Image("ghcr.io/labring-actions/devbox/go-1.22:latest").
+//		Resources(2, 4).
+//		Port("http", 8080).
+//		Env("GOFLAGS", "-mod=mod").
+//		Create(ctx)
+type DevboxBuilder struct {
+	sdk *DevboxSDK
+	cfg DevboxConfig
+}
+
+// NewBuilder returns a builder for a devbox named name in the namespace of
+// sdk.
+func NewBuilder(sdk *DevboxSDK, name string) *DevboxBuilder {
+	return &DevboxBuilder{sdk: sdk, cfg: DevboxConfig{Name: name}}
+}
+
+// Image sets the runtime image.
+func (b *DevboxBuilder) Image(image string) *DevboxBuilder {
+	b.cfg.Image = image
+	return b
+}
+
+// Resources sets the CPU limit in cores and the memory limit in GB.
+func (b *DevboxBuilder) Resources(cpu, memory float64) *DevboxBuilder {
+	b.cfg.CPU = cpu
+	b.cfg.Memory = memory
+	return b
+}
+
+// Port exposes a TCP container port.
+func (b *DevboxBuilder) Port(name string, port int32) *DevboxBuilder {
+	b.cfg.Ports = append(b.cfg.Ports, corev1.ContainerPort{
+		Name:          name,
+		ContainerPort: port,
+		Protocol:      corev1.ProtocolTCP,
+	})
+	return b
+}
+
+// AppPort adds a service port for an application running in the devbox.
+func (b *DevboxBuilder) AppPort(port corev1.ServicePort) *DevboxBuilder {
+	b.cfg.AppPorts = append(b.cfg.AppPorts, port)
+	return b
+}
+
+// Env sets an environment variable.
+func (b *DevboxBuilder) Env(name, value string) *DevboxBuilder {
+	if b.cfg.Env == nil {
+		b.cfg.Env = make(map[string]string)
+	}
+	b.cfg.Env[name] = value
+	return b
+}
+
+// WorkingDir sets the working directory of the devbox container.
+func (b *DevboxBuilder) WorkingDir(dir string) *DevboxBuilder {
+	b.cfg.WorkingDir = dir
+	return b
+}
+
+// User sets the user the devbox runs as.
+func (b *DevboxBuilder) User(user string) *DevboxBuilder {
+	b.cfg.User = user
+	return b
+}
+
+// NetworkType sets how the devbox SSH port is exposed.
+func (b *DevboxBuilder) NetworkType(t v1alpha2.NetworkType) *DevboxBuilder {
+	b.cfg.NetworkType = t
+	return b
+}
+
+// Label sets a label on the devbox.
+func (b *DevboxBuilder) Label(key, value string) *DevboxBuilder {
+	if b.cfg.Labels == nil {
+		b.cfg.Labels = make(map[string]string)
+	}
+	b.cfg.Labels[key] = value
+	return b
+}
+
+// Annotation sets an annotation on the devbox.
+func (b *DevboxBuilder) Annotation(key, value string) *DevboxBuilder {
+	if b.cfg.Annotations == nil {
+		b.cfg.Annotations = make(map[string]string)
+	}
+	b.cfg.Annotations[key] = value
+	return b
+}
+
+// Config returns the configuration assembled so far.
+func (b *DevboxBuilder) Config() DevboxConfig {
+	return b.cfg
+}
+
+// Create creates the devbox. The name and image are required.
+func (b *DevboxBuilder) Create(ctx context.Context) (*Devbox, error) {
+	if b.cfg.Name == "" {
+		return nil, errors.New("devbox name is required")
+	}
+	if b.cfg.Image == "" {
+		return nil, errors.New("devbox image is required")
+	}
+	return b.sdk.CreateDevbox(ctx, b.cfg)
+}
@@ -0,0 +1,163 @@
+package devbox
+
+import (
+	"context"
+	"errors"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/gitlayzer/devbox-sdk-go/api/v1alpha2"
+)
+
+// CacheMode selects how the SDK keeps its devbox cache.
+type CacheMode int
+
+const (
+	// CachePassive updates the cache with every devbox the SDK reads or
+	// writes, and never invalidates it otherwise. It is the default.
+	CachePassive CacheMode = iota
+	// CacheInformer runs a shared informer that keeps the cache in sync with
+	// the cluster, so lookups are answered without API requests. Start it
+	// with StartCacheInformer.
+	CacheInformer
+	// CacheDisabled never answers lookups from the cache; every lookup is an
+	// API request.
+	CacheDisabled
+)
+
+// WithCacheMode sets the cache mode of the SDK.
+func WithCacheMode(mode CacheMode) DevboxSDKOption {
+	return func(s *DevboxSDK) {
+		s.cacheMode = mode
+	}
+}
+
+// StartCacheInformer starts the devbox informer of an SDK in CacheInformer
+// mode and waits until the cache holds every devbox of the namespace. The
+// informer runs until ctx is cancelled or StopCacheInformer is called, after
+// which lookups go to the API server again and the cache falls back to
+// passive updates. It fails if an informer already keeps the cache in sync;
+// SDK copies that share a cache share its informer. In other modes it does
+// nothing.
+func (s *DevboxSDK) StartCacheInformer(ctx context.Context) error {
+	if s.cacheMode != CacheInformer {
+		return nil
+	}
+
+	ctx, stop := context.WithCancel(ctx)
+	informer := cache.NewSharedIndexInformer(&cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			return s.client.List(ctx, opts)
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			return s.client.Watch(ctx, opts)
+		},
+	}, &v1alpha2.Devbox{}, 0, cache.Indexers{})
+
+	if !s.cache.setInformer(informer, stop) {
+		stop()
+		return errors.New("devbox cache informer is already running")
+	}
+
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if crd, ok := obj.(*v1alpha2.Devbox); ok {
+				s.cache.Set(crd.Name, crd.DeepCopy())
+			}
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			if crd, ok := obj.(*v1alpha2.Devbox); ok {
+				s.cache.Set(crd.Name, crd.DeepCopy())
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if crd, ok := obj.(*v1alpha2.Devbox); ok {
+				s.cache.Delete(crd.Name)
+			}
+		},
+	})
+	if err != nil {
+		s.cache.clearInformer(informer)
+		stop()
+		return err
+	}
+
+	go func() {
+		informer.Run(ctx.Done())
+		s.cache.clearInformer(informer)
+		stop()
+	}()
+	if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+		stop()
+		return errors.New("devbox cache informer did not sync")
+	}
+	return nil
+}
+
+// StopCacheInformer stops the informer started by StartCacheInformer for the
+// cache of the SDK, if one is running.
+func (s *DevboxSDK) StopCacheInformer() {
+	s.cache.mu.Lock()
+	stop := s.cache.stopInformer
+	s.cache.mu.Unlock()
+	if stop != nil {
+		stop()
+	}
+}
+
+// getDevbox returns the CRD of the named devbox, from the cache if a synced
+// informer keeps it up to date and from the API server otherwise.
+func (s *DevboxSDK) getDevbox(ctx context.Context, name string) (*v1alpha2.Devbox, error) {
+	if s.cacheMode == CacheInformer && s.informerSynced() {
+		crd, ok := s.cache.Get(name)
+		s.metrics.recordCacheLookup(ok)
+		if ok {
+			return crd.DeepCopy(), nil
+		}
+	}
+	crd, err := s.client.Get(ctx, name)
+	if err != nil {
+		return nil, apiError(err)
+	}
+	if s.cacheMode != CacheDisabled {
+		s.cache.Set(crd.Name, crd)
+	}
+	return crd, nil
+}
+
+// informerSynced reports whether an informer is running for the cache of the
+// SDK and has synced it.
+func (s *DevboxSDK) informerSynced() bool {
+	s.cache.mu.Lock()
+	informer := s.cache.informer
+	s.cache.mu.Unlock()
+	return informer != nil && informer.HasSynced()
+}
+
+// setInformer records informer, stopped by stop, as keeping c in sync,
+// unless another informer already does.
+func (c *devboxCache) setInformer(informer cache.SharedIndexInformer, stop context.CancelFunc) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.informer != nil {
+		return false
+	}
+	c.informer, c.stopInformer = informer, stop
+	return true
+}
+
+// clearInformer forgets informer once it has stopped, unless another one has
+// taken its place.
+func (c *devboxCache) clearInformer(informer cache.SharedIndexInformer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.informer == informer {
+		c.informer, c.stopInformer = nil, nil
+	}
+}
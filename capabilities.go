@@ -0,0 +1,83 @@
+package devbox
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// ContainerCapabilities is the security context of the devbox container.
+type ContainerCapabilities struct {
+	Add                      []string
+	Drop                     []string
+	Privileged               bool
+	RunAsUser                *int64
+	RunAsGroup               *int64
+	AllowPrivilegeEscalation *bool
+}
+
+// GetContainerCapabilities returns the security context of the devbox
+// container as the running pod has it.
+func (d *Devbox) GetContainerCapabilities(ctx context.Context) (*ContainerCapabilities, error) {
+	pod, err := d.getPod(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c := d.mainContainer(pod)
+	if c == nil {
+		return nil, ErrContainerNotRunning
+	}
+
+	caps := &ContainerCapabilities{}
+	sc := c.SecurityContext
+	if sc == nil {
+		return caps, nil
+	}
+	if sc.Capabilities != nil {
+		caps.Add = capabilityNames(sc.Capabilities.Add)
+		caps.Drop = capabilityNames(sc.Capabilities.Drop)
+	}
+	if sc.Privileged != nil {
+		caps.Privileged = *sc.Privileged
+	}
+	caps.RunAsUser = sc.RunAsUser
+	caps.RunAsGroup = sc.RunAsGroup
+	caps.AllowPrivilegeEscalation = sc.AllowPrivilegeEscalation
+	return caps, nil
+}
+
+// SetContainerCapabilities replaces the security context of the devbox
+// container; nil fields are unset. The change applies when the pod is next
+// created. It returns ErrPrivilegedNotAllowed if a privileged container is
+// requested and the API server rejects it, e.g. due to PodSecurity admission.
+func (d *Devbox) SetContainerCapabilities(ctx context.Context, caps ContainerCapabilities) error {
+	securityContext := map[string]interface{}{
+		"capabilities": map[string]interface{}{
+			"add":  caps.Add,
+			"drop": caps.Drop,
+		},
+		"privileged":               caps.Privileged,
+		"runAsUser":                caps.RunAsUser,
+		"runAsGroup":               caps.RunAsGroup,
+		"allowPrivilegeEscalation": caps.AllowPrivilegeEscalation,
+	}
+
+	err := d.patchConfig(ctx, map[string]interface{}{"securityContext": securityContext})
+	if caps.Privileged && (apierrors.IsForbidden(err) || apierrors.IsInvalid(err)) {
+		return fmt.Errorf("%w: %w", ErrPrivilegedNotAllowed, err)
+	}
+	return err
+}
+
+func capabilityNames(capabilities []corev1.Capability) []string {
+	if len(capabilities) == 0 {
+		return nil
+	}
+	names := make([]string, len(capabilities))
+	for i, c := range capabilities {
+		names[i] = string(c)
+	}
+	return names
+}
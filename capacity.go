@@ -0,0 +1,100 @@
+package devbox
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gitlayzer/devbox-sdk-go/api/v1alpha2"
+)
+
+// ClusterCapacity summarizes allocatable and free resources across the
+// cluster nodes. CPU is in cores and memory in GB. LargestAllocatableCPU and
+// LargestAllocatableMemGiB are the free resources of the schedulable node
+// with the most free CPU, which bounds the largest devbox that can currently
+// be scheduled.
+type ClusterCapacity struct {
+	TotalCPU                 float64
+	FreeCPU                  float64
+	TotalMemGiB              float64
+	FreeMemGiB               float64
+	NodeCount                int
+	SchedulableNodeCount     int
+	LargestAllocatableCPU    float64
+	LargestAllocatableMemGiB float64
+}
+
+// GetClusterCapacity sums the allocatable resources of all nodes and subtracts
+// the requests of the running and pending devboxes in the SDK namespace. A
+// devbox without requests counts with its limits. Other workloads are not
+// accounted for, so the free figures are an upper bound.
+func (s *DevboxSDK) GetClusterCapacity(ctx context.Context) (*ClusterCapacity, error) {
+	nodes, err := s.client.Clientset().CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	list, err := s.client.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	usedCPU := make(map[string]float64)
+	usedMem := make(map[string]float64)
+	for _, d := range s.wrapList(list, nil) {
+		switch d.crd.Status.Phase {
+		case v1alpha2.DevboxPhaseRunning, v1alpha2.DevboxPhasePending:
+		default:
+			continue
+		}
+		if d.crd.Status.Node == "" {
+			// Not scheduled yet, so not using any node's resources.
+			continue
+		}
+		cpu, mem := d.CPURequest(), d.MemoryRequest()
+		if cpu == 0 {
+			cpu = d.CPULimit()
+		}
+		if mem == 0 {
+			mem = d.MemoryLimit()
+		}
+		usedCPU[d.crd.Status.Node] += cpu
+		usedMem[d.crd.Status.Node] += mem
+	}
+
+	capacity := &ClusterCapacity{NodeCount: len(nodes.Items)}
+	for _, node := range nodes.Items {
+		cpu := cores(*node.Status.Allocatable.Cpu())
+		mem := gigabytes(*node.Status.Allocatable.Memory())
+		freeCPU := max(cpu-usedCPU[node.Name], 0)
+		freeMem := max(mem-usedMem[node.Name], 0)
+
+		capacity.TotalCPU += cpu
+		capacity.TotalMemGiB += mem
+		capacity.FreeCPU += freeCPU
+		capacity.FreeMemGiB += freeMem
+
+		if !nodeSchedulable(&node) {
+			continue
+		}
+		capacity.SchedulableNodeCount++
+		if freeCPU > capacity.LargestAllocatableCPU {
+			capacity.LargestAllocatableCPU = freeCPU
+			capacity.LargestAllocatableMemGiB = freeMem
+		}
+	}
+	return capacity, nil
+}
+
+// nodeSchedulable reports whether new pods can be scheduled on node.
+func nodeSchedulable(node *corev1.Node) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+	for _, c := range node.Status.Conditions {
+		if c.Type == corev1.NodeReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package devbox
+
+import (
+	"net/http"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gitlayzer/devbox-sdk-go/client"
+)
+
+// WithNamespace binds the SDK to namespace.
+func WithNamespace(namespace string) DevboxSDKOption {
+	return func(s *DevboxSDK) {
+		s.namespace = namespace
+	}
+}
+
+// DryRunOption makes every mutating Kubernetes request of the SDK a server-side
+// dry run: requests are validated and admitted but never persisted.
+func DryRunOption() DevboxSDKOption {
+	return func(s *DevboxSDK) {
+		s.wrapTransport(func(rt http.RoundTripper) http.RoundTripper {
+			return &dryRunTransport{next: rt}
+		})
+	}
+}
+
+// CloneSDK returns a shallow copy of the SDK with opts applied.
+//
+// The clone shares the underlying HTTP connection pool with s, since client-go
+// caches transports per TLS configuration. The audit log writer is shared
+// unless overridden. The Kubernetes client and the devbox cache are shared
+// only as long as the options leave the namespace and the rest config alone;
+// WithNamespace and DryRunOption give the clone its own client, and
+// WithNamespace also its own cache.
+func (s *DevboxSDK) CloneSDK(opts ...DevboxSDKOption) (*DevboxSDK, error) {
+	clone := *s
+	for _, opt := range opts {
+		opt(&clone)
+	}
+
+	switch {
+	case clone.restConfig != s.restConfig:
+		c, dc, err := newClients(clone.restConfig, clone.namespace)
+		if err != nil {
+			return nil, err
+		}
+		clone.client, clone.dynamic = c, dc
+	case clone.namespace != s.namespace:
+		c, err := client.New(clone.restConfig, clone.namespace)
+		if err != nil {
+			return nil, err
+		}
+		clone.client = c
+	}
+	if clone.namespace != s.namespace {
+		clone.cache = newDevboxCache()
+	}
+	return &clone, nil
+}
+
+// dryRunTransport adds dryRun=All to every mutating request.
+type dryRunTransport struct {
+	next http.RoundTripper
+}
+
+func (t *dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return t.next.RoundTrip(req)
+	}
+
+	req = req.Clone(req.Context())
+	query := req.URL.Query()
+	query.Set("dryRun", metav1.DryRunAll)
+	req.URL.RawQuery = query.Encode()
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,79 @@
+package devbox
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// usedBytesCommand prints the bytes used on the root filesystem.
+const usedBytesCommand = "df -B1 --output=used / | tail -n 1"
+
+// trimCommand discards unused blocks on all mounted filesystems that support
+// it. It needs CAP_SYS_ADMIN, so failures are ignored.
+const trimCommand = "fstrim -a >/dev/null 2>&1 || true"
+
+// cleanCachesCommand removes temporary files and package manager caches. Each
+// tool is only invoked if installed.
+const cleanCachesCommand = `rm -rf "$HOME"/.cache/* /tmp/* /var/tmp/* 2>/dev/null
+command -v apt-get >/dev/null && apt-get clean >/dev/null 2>&1
+command -v dnf >/dev/null && dnf clean all >/dev/null 2>&1
+command -v yum >/dev/null && yum clean all >/dev/null 2>&1
+command -v apk >/dev/null && rm -rf /var/cache/apk/* 2>/dev/null
+command -v npm >/dev/null && npm cache clean --force >/dev/null 2>&1
+command -v pip >/dev/null && pip cache purge >/dev/null 2>&1
+command -v go >/dev/null && go clean -cache -modcache >/dev/null 2>&1
+true`
+
+// CompactOptions contains options for Compact.
+type CompactOptions struct {
+	// CleanCaches removes ~/.cache, /tmp, /var/tmp and package manager caches.
+	CleanCaches bool
+}
+
+// CompactResult reports what Compact achieved. BytesFreed is the drop in used
+// space on the root filesystem and may be negative if the devbox wrote data
+// while Compact ran.
+type CompactResult struct {
+	BytesFreed int64
+	Duration   time.Duration
+}
+
+// Compact reclaims filesystem space in the devbox, typically before creating a
+// release. It trims unused blocks with fstrim and, if requested, removes cache
+// directories. Zeroing free ext4 blocks with zerofree is not attempted, since
+// it requires the filesystem to be unmounted or read-only.
+func (d *Devbox) Compact(ctx context.Context, opts CompactOptions) (*CompactResult, error) {
+	start := time.Now()
+
+	script := []string{"before=$(" + usedBytesCommand + ")"}
+	if opts.CleanCaches {
+		script = append(script, cleanCachesCommand)
+	}
+	script = append(script, trimCommand, `echo "$before" "$(`+usedBytesCommand+`)"`)
+
+	out, err := d.runSSH(ctx, "sh -c "+shellQuote(strings.Join(script, "\n")), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := strings.Fields(string(out))
+	if len(fields) != 2 {
+		return nil, fmt.Errorf("unexpected compact output: %q", out)
+	}
+	before, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected compact output: %q", out)
+	}
+	after, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected compact output: %q", out)
+	}
+
+	return &CompactResult{
+		BytesFreed: before - after,
+		Duration:   time.Since(start),
+	}, nil
+}
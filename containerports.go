@@ -0,0 +1,129 @@
+package devbox
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// listeningSocketsCommand prints the kernel socket tables, each preceded by a
+// "# <protocol>" line.
+const listeningSocketsCommand = "for p in tcp tcp6 udp udp6; do echo \"# $p\"; cat /proc/net/$p 2>/dev/null; done"
+
+// Socket states in /proc/net/{tcp,udp}: TCP_LISTEN, and TCP_CLOSE which bound
+// but unconnected UDP sockets report.
+const (
+	socketStateListen = "0A"
+	socketStateClose  = "07"
+)
+
+// PortBinding is a port the devbox container listens on or has mapped to its
+// node. HostPort is nil unless the port is mapped with hostPort.
+type PortBinding struct {
+	ContainerPort int32
+	Protocol      string
+	HostPort      *int32
+	HostIP        string
+}
+
+// GetContainerPorts returns the ports the devbox container is actually
+// listening on, read from the kernel socket tables over SSH, together with any
+// ports mapped to the node with hostPort. Unlike Ports, it includes ports not
+// declared in the spec. The result is sorted by port and protocol.
+func (d *Devbox) GetContainerPorts(ctx context.Context) ([]PortBinding, error) {
+	pod, err := d.getPod(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out, err := d.runSSH(ctx, listeningSocketsCommand, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct {
+		port     int32
+		protocol string
+	}
+	bindings := make(map[key]*PortBinding)
+	for _, l := range parseListeningSockets(out) {
+		k := key{l.ContainerPort, l.Protocol}
+		if bindings[k] == nil {
+			binding := l
+			bindings[k] = &binding
+		}
+	}
+
+	if c := d.mainContainer(pod); c != nil {
+		for _, p := range c.Ports {
+			if p.HostPort == 0 {
+				continue
+			}
+			protocol := string(p.Protocol)
+			if protocol == "" {
+				protocol = string(corev1.ProtocolTCP)
+			}
+			k := key{p.ContainerPort, protocol}
+			if bindings[k] == nil {
+				bindings[k] = &PortBinding{ContainerPort: p.ContainerPort, Protocol: protocol}
+			}
+			hostPort := p.HostPort
+			bindings[k].HostPort = &hostPort
+			bindings[k].HostIP = p.HostIP
+		}
+	}
+
+	result := make([]PortBinding, 0, len(bindings))
+	for _, b := range bindings {
+		result = append(result, *b)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].ContainerPort != result[j].ContainerPort {
+			return result[i].ContainerPort < result[j].ContainerPort
+		}
+		return result[i].Protocol < result[j].Protocol
+	})
+	return result, nil
+}
+
+// parseListeningSockets parses the output of listeningSocketsCommand into one
+// binding per listening socket. Duplicates, e.g. the same port on IPv4 and
+// IPv6, are not removed.
+func parseListeningSockets(data []byte) []PortBinding {
+	var bindings []PortBinding
+	var protocol, listenState string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if name, ok := strings.CutPrefix(line, "# "); ok {
+			protocol = string(corev1.ProtocolTCP)
+			listenState = socketStateListen
+			if strings.HasPrefix(name, "udp") {
+				protocol = string(corev1.ProtocolUDP)
+				listenState = socketStateClose
+			}
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 4 || fields[3] != listenState {
+			// Header line or socket not listening
+			continue
+		}
+		_, portHex, ok := strings.Cut(fields[1], ":")
+		if !ok {
+			continue
+		}
+		port, err := strconv.ParseUint(portHex, 16, 16)
+		if err != nil || port == 0 {
+			continue
+		}
+		bindings = append(bindings, PortBinding{ContainerPort: int32(port), Protocol: protocol})
+	}
+	return bindings
+}
@@ -0,0 +1,37 @@
+package devbox
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+)
+
+// CopyBetween copies the file or directory at srcPath on d into the directory
+// destPath on dest, creating destPath if needed. The data is streamed as a
+// gzip-compressed tarball from one SSH session to the other and never held in
+// memory as a whole. It fails with an error matching ErrSSHUnavailable if
+// either devbox cannot be reached.
+func (d *Devbox) CopyBetween(ctx context.Context, srcPath string, dest *Devbox, destPath string) error {
+	cleaned := path.Clean(srcPath)
+	download := "tar -czf - -C " + shellQuote(path.Dir(cleaned)) + " " + shellQuote(path.Base(cleaned))
+
+	pr, pw := io.Pipe()
+	downloaded := make(chan error, 1)
+	go func() {
+		err := d.streamSSH(ctx, download, nil, pw)
+		pw.CloseWithError(err)
+		downloaded <- err
+	}()
+
+	uploadErr := dest.UploadTarball(ctx, pr, destPath)
+	// Unblock the download if the upload stopped reading early.
+	pr.CloseWithError(uploadErr)
+
+	downloadErr := <-downloaded
+	if downloadErr != nil && !errors.Is(downloadErr, uploadErr) && !errors.Is(downloadErr, io.ErrClosedPipe) {
+		// The download failed on its own; any upload error is a consequence.
+		return downloadErr
+	}
+	return uploadErr
+}
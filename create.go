@@ -0,0 +1,163 @@
+package devbox
+
+import (
+	"context"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/gitlayzer/devbox-sdk-go/api/v1alpha2"
+)
+
+// DevboxConfig contains configuration for creating a devbox.
+type DevboxConfig struct {
+	Name        string                 `json:"name"`
+	Image       string                 `json:"image"`
+	CPU         float64                `json:"cpu,omitempty"`    // cores
+	Memory      float64                `json:"memory,omitempty"` // GB
+	Ports       []corev1.ContainerPort `json:"ports,omitempty"`
+	AppPorts    []corev1.ServicePort   `json:"appPorts,omitempty"`
+	Env         map[string]string      `json:"env,omitempty"`
+	WorkingDir  string                 `json:"workingDir,omitempty"`
+	User        string                 `json:"user,omitempty"`
+	NetworkType v1alpha2.NetworkType   `json:"networkType,omitempty"`
+	Labels      map[string]string      `json:"labels,omitempty"`
+	Annotations map[string]string      `json:"annotations,omitempty"`
+}
+
+// CreateDevbox creates a new devbox in the SDK namespace.
+func (s *DevboxSDK) CreateDevbox(ctx context.Context, cfg DevboxConfig) (*Devbox, error) {
+	created, err := s.client.Create(ctx, cfg.devbox(s.namespace))
+	s.metrics.recordCreation(err)
+	if err != nil {
+		return nil, apiError(err)
+	}
+	s.cache.Set(created.Name, created)
+	return newDevbox(created, s), nil
+}
+
+// EnsureDevbox returns the devbox named cfg.Name, creating it if it does not
+// exist. If it exists with a different image or resources, it is patched to
+// match cfg. It is safe to call concurrently for the same name.
+func (s *DevboxSDK) EnsureDevbox(ctx context.Context, cfg DevboxConfig) (*Devbox, error) {
+	d, _, err := s.ensureDevbox(ctx, cfg)
+	return d, err
+}
+
+// ensureOutcome describes what ensureDevbox had to do.
+type ensureOutcome int
+
+const (
+	ensureUnchanged ensureOutcome = iota
+	ensureCreated
+	ensureUpdated
+)
+
+func (s *DevboxSDK) ensureDevbox(ctx context.Context, cfg DevboxConfig) (d *Devbox, outcome ensureOutcome, err error) {
+	// Losing a race against another caller, either to create the devbox or
+	// to patch it, is retried with a bounded backoff, taking the get path.
+	lostRace := func(err error) bool {
+		return apierrors.IsAlreadyExists(err) || apierrors.IsConflict(err)
+	}
+	err = retry.OnError(retry.DefaultBackoff, lostRace, func() error {
+		crd, err := s.client.Get(ctx, cfg.Name)
+		if apierrors.IsNotFound(err) {
+			d, err = s.CreateDevbox(ctx, cfg)
+			outcome = ensureCreated
+			return err
+		}
+		if err != nil {
+			return apiError(err)
+		}
+		s.cache.Set(crd.Name, crd)
+		d = newDevbox(crd, s)
+		changed, err := d.reconcileConfig(ctx, cfg)
+		outcome = ensureUnchanged
+		if changed {
+			outcome = ensureUpdated
+		}
+		return err
+	})
+	if err != nil {
+		return nil, ensureUnchanged, err
+	}
+	return d, outcome, nil
+}
+
+// reconcileConfig updates the image and resources of the devbox if they differ
+// from cfg, reporting whether an update was made. Zero values in cfg are
+// ignored. Resources are compared as quantities, so that "1" and "1000m" CPU
+// match. Both fields are written in a single server-side apply, so the devbox
+// is never left with only one of them updated.
+func (d *Devbox) reconcileConfig(ctx context.Context, cfg DevboxConfig) (bool, error) {
+	want := resourceList(cfg.CPU, cfg.Memory)
+	changed := cfg.Image != "" && cfg.Image != d.Image()
+	for name, quantity := range want {
+		if current, ok := d.crd.Spec.Resource[name]; !ok || current.Cmp(quantity) != 0 {
+			changed = true
+		}
+	}
+	if !changed {
+		return false, nil
+	}
+
+	// The manager applies both fields every time, so that neither is
+	// removed by an apply that only needed to change the other.
+	err := d.apply(ctx, d.sdk.managerName()+"-ensure", func() map[string]interface{} {
+		image := cfg.Image
+		if image == "" {
+			image = d.Image()
+		}
+		return map[string]interface{}{
+			"image":    image,
+			"resource": d.resourcesWith(want),
+		}
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// devbox builds the CRD object described by cfg.
+func (cfg DevboxConfig) devbox(namespace string) *v1alpha2.Devbox {
+	devbox := &v1alpha2.Devbox{}
+	devbox.Name = cfg.Name
+	devbox.Namespace = namespace
+	devbox.Labels = copyStringMap(cfg.Labels)
+	devbox.Annotations = copyStringMap(cfg.Annotations)
+	devbox.Spec = v1alpha2.DevboxSpec{
+		State:    v1alpha2.DevboxStateRunning,
+		Image:    cfg.Image,
+		Resource: resourceList(cfg.CPU, cfg.Memory),
+		Config: v1alpha2.Config{
+			User:       cfg.User,
+			WorkingDir: cfg.WorkingDir,
+			Ports:      cfg.Ports,
+			AppPorts:   cfg.AppPorts,
+			Env:        envVars(cfg.Env),
+		},
+		NetworkSpec: v1alpha2.NetworkSpec{Type: cfg.NetworkType},
+	}
+	return devbox
+}
+
+// envVars converts an environment map to a list sorted by name.
+func envVars(env map[string]string) []corev1.EnvVar {
+	if len(env) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	vars := make([]corev1.EnvVar, len(keys))
+	for i, k := range keys {
+		vars[i] = corev1.EnvVar{Name: k, Value: env[k]}
+	}
+	return vars
+}
@@ -0,0 +1,138 @@
+package devbox
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// DebugContainerConfig contains configuration for an ephemeral debug container.
+type DebugContainerConfig struct {
+	// Name defaults to "debugger-<unix time>".
+	Name    string
+	Image   string
+	Command []string
+	Env     map[string]string
+
+	// Stdin, if set, is streamed to the container; the container exits once
+	// it is exhausted. Stdout and Stderr receive the container output.
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// AttachDebugContainer adds an ephemeral container sharing the process
+// namespace of the devbox container and streams its I/O until it exits or ctx
+// is cancelled.
+func (d *Devbox) AttachDebugContainer(ctx context.Context, cfg DebugContainerConfig) error {
+	name := cfg.Name
+	if name == "" {
+		name = "debugger-" + strconv.FormatInt(time.Now().Unix(), 10)
+	}
+
+	pod, err := d.getPod(ctx)
+	if err != nil {
+		return err
+	}
+	pod.Spec.EphemeralContainers = append(pod.Spec.EphemeralContainers, corev1.EphemeralContainer{
+		EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+			Name:      name,
+			Image:     cfg.Image,
+			Command:   cfg.Command,
+			Env:       envVars(cfg.Env),
+			Stdin:     cfg.Stdin != nil,
+			StdinOnce: cfg.Stdin != nil,
+		},
+		TargetContainerName: d.crd.Name,
+	})
+
+	pods := d.sdk.client.Clientset().CoreV1().Pods(pod.Namespace)
+	_, err = pods.UpdateEphemeralContainers(ctx, pod.Name, pod, metav1.UpdateOptions{})
+	if apierrors.IsNotFound(err) || apierrors.IsMethodNotSupported(err) {
+		return ErrEphemeralContainersNotSupported
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := d.waitEphemeralStarted(ctx, pod.Name, name); err != nil {
+		return err
+	}
+
+	req := d.sdk.client.Clientset().CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("attach").
+		VersionedParams(&corev1.PodAttachOptions{
+			Container: name,
+			Stdin:     cfg.Stdin != nil,
+			Stdout:    cfg.Stdout != nil,
+			Stderr:    cfg.Stderr != nil,
+		}, scheme.ParameterCodec)
+	executor, err := remotecommand.NewSPDYExecutor(d.sdk.restConfig, "POST", req.URL())
+	if err != nil {
+		return err
+	}
+	return executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdin:  cfg.Stdin,
+		Stdout: cfg.Stdout,
+		Stderr: cfg.Stderr,
+	})
+}
+
+// DetachDebugContainer checks that the named debug container has exited.
+// Kubernetes does not allow ephemeral containers to be removed from a pod, so
+// its record stays in the pod status until the devbox pod is recreated.
+func (d *Devbox) DetachDebugContainer(ctx context.Context, name string) error {
+	pod, err := d.getPod(ctx)
+	if err != nil {
+		return err
+	}
+	for _, status := range pod.Status.EphemeralContainerStatuses {
+		if status.Name != name {
+			continue
+		}
+		if status.State.Terminated == nil {
+			return fmt.Errorf("debug container %q is still running; it stops when its command exits", name)
+		}
+		return nil
+	}
+	return fmt.Errorf("debug container %q not found", name)
+}
+
+// waitEphemeralStarted polls the pod until the named ephemeral container runs.
+func (d *Devbox) waitEphemeralStarted(ctx context.Context, podName, name string) error {
+	pods := d.sdk.client.Clientset().CoreV1().Pods(d.crd.Namespace)
+	for {
+		pod, err := pods.Get(ctx, podName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		for _, status := range pod.Status.EphemeralContainerStatuses {
+			if status.Name != name {
+				continue
+			}
+			if status.State.Running != nil {
+				return nil
+			}
+			if t := status.State.Terminated; t != nil {
+				return fmt.Errorf("debug container %q exited: %s", name, t.Reason)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
@@ -2,9 +2,14 @@ package devbox
 
 import (
 	"context"
+	"errors"
+	"log/slog"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/gitlayzer/devbox-sdk-go/api/v1alpha2"
 	"github.com/gitlayzer/devbox-sdk-go/types"
@@ -110,24 +115,68 @@ func (d *Devbox) AppPorts() []corev1.ServicePort {
 	return d.crd.Spec.Config.AppPorts
 }
 
+// GetResourceVersion returns the resource version of the cached CRD. Mutating
+// methods send it along, so they fail with a ConflictError when the devbox was
+// changed elsewhere; callers should RefreshInfo and retry.
+func (d *Devbox) GetResourceVersion() string {
+	return d.crd.ResourceVersion
+}
+
 // CRD returns the underlying CRD object.
 func (d *Devbox) CRD() *v1alpha2.Devbox {
 	return d.crd
 }
 
-// RefreshInfo refreshes the devbox info from Kubernetes.
+// ownerReference returns a controller reference to the devbox, so that
+// dependent objects are garbage-collected with it.
+func (d *Devbox) ownerReference() metav1.OwnerReference {
+	controller := true
+	return metav1.OwnerReference{
+		APIVersion: v1alpha2.GroupVersion.String(),
+		Kind:       "Devbox",
+		Name:       d.crd.Name,
+		UID:        d.crd.UID,
+		Controller: &controller,
+	}
+}
+
+// RefreshInfo refreshes the devbox info from Kubernetes and records the
+// access for GarbageCollect.
 func (d *Devbox) RefreshInfo(ctx context.Context) error {
 	devbox, err := d.sdk.client.Get(ctx, d.crd.Name)
 	if err != nil {
-		return err
+		return apiError(err)
 	}
 	d.crd = devbox
 	d.sdk.cache.Set(d.crd.Name, devbox)
+	d.noteAccess(ctx)
 	return nil
 }
 
 // WaitForReady waits for the devbox to become ready.
 func (d *Devbox) WaitForReady(ctx context.Context, opts types.WaitForReadyOptions) error {
+	return d.waitUntil(ctx, opts, "waiting for devbox to be ready", d.isReady)
+}
+
+// waitUntil polls the devbox until cond returns true, using the backoff
+// settings in opts.
+func (d *Devbox) waitUntil(ctx context.Context, opts types.WaitForReadyOptions, what string, cond func() bool) error {
+	return d.sdk.pollWithBackoff(ctx, opts, what, func() (bool, error) {
+		if err := d.RefreshInfo(ctx); err != nil {
+			return false, err
+		}
+		return cond(), nil
+	})
+}
+
+// pollWithBackoff calls check until it returns true or an error, using the
+// backoff settings in opts.
+func (s *DevboxSDK) pollWithBackoff(ctx context.Context, opts types.WaitForReadyOptions, what string, check func() (bool, error)) (err error) {
+	ctx, span := s.startSpan(ctx, "devbox.Wait", attribute.String("devbox.wait", what))
+	defer func() { endSpan(span, err) }()
+	start := time.Now()
+	defer func() { s.metrics.recordWait(time.Since(start), err) }()
+
 	// Set defaults
 	timeout := opts.Timeout
 	if timeout == 0 {
@@ -163,18 +212,27 @@ func (d *Devbox) WaitForReady(ctx context.Context, opts types.WaitForReadyOption
 		interval = opts.CheckInterval
 	}
 
-	for {
+	for attempt := 1; ; attempt++ {
 		if time.Now().After(deadline) {
-			return &TimeoutError{message: "waiting for devbox to be ready", timeout: timeout}
+			s.logAttrs(ctx, slog.LevelWarn, "wait timed out", slog.String("wait", what), slog.Duration("timeout", timeout))
+			return &TimeoutError{message: what, timeout: timeout, err: context.DeadlineExceeded}
 		}
 
-		// Refresh info
-		if err := d.RefreshInfo(ctx); err != nil {
+		// Check condition
+		done, err := check()
+		s.logAttrs(ctx, slog.LevelDebug, "wait check",
+			slog.String("wait", what),
+			slog.Int("attempt", attempt),
+			slog.Bool("done", done),
+			slog.Duration("elapsed", time.Since(start)),
+			errorAttr(err))
+		span.AddEvent("check", trace.WithAttributes(
+			attribute.Int("attempt", attempt),
+			attribute.Bool("done", done)))
+		if err != nil {
 			return err
 		}
-
-		// Check if ready
-		if d.isReady() {
+		if done {
 			return nil
 		}
 
@@ -200,43 +258,84 @@ func (d *Devbox) isReady() bool {
 	return d.crd.Status.Phase == v1alpha2.DevboxPhaseRunning
 }
 
+// isStoppedOrPaused checks if the devbox is in a phase where its pod spec can change.
+func (d *Devbox) isStoppedOrPaused() bool {
+	switch d.crd.Status.Phase {
+	case v1alpha2.DevboxPhaseStopped, v1alpha2.DevboxPhasePaused:
+		return true
+	default:
+		return false
+	}
+}
+
 // Start starts the devbox.
 func (d *Devbox) Start(ctx context.Context) error {
-	return d.sdk.client.UpdateState(ctx, d.crd.Name, v1alpha2.DevboxStateRunning)
+	return d.updateState(ctx, v1alpha2.DevboxStateRunning)
 }
 
 // Pause pauses the devbox.
 func (d *Devbox) Pause(ctx context.Context) error {
-	return d.sdk.client.UpdateState(ctx, d.crd.Name, v1alpha2.DevboxStatePaused)
+	return d.updateState(ctx, v1alpha2.DevboxStatePaused)
 }
 
 // Stop stops the devbox.
 func (d *Devbox) Stop(ctx context.Context) error {
-	return d.sdk.client.UpdateState(ctx, d.crd.Name, v1alpha2.DevboxStateStopped)
+	return d.updateState(ctx, v1alpha2.DevboxStateStopped)
 }
 
 // Shutdown shuts down the devbox (releases all resources).
 func (d *Devbox) Shutdown(ctx context.Context) error {
-	return d.sdk.client.UpdateState(ctx, d.crd.Name, v1alpha2.DevboxStateShutdown)
+	return d.updateState(ctx, v1alpha2.DevboxStateShutdown)
 }
 
-// Delete deletes the devbox.
-func (d *Devbox) Delete(ctx context.Context) error {
-	if err := d.sdk.client.Delete(ctx, d.crd.Name); err != nil {
+// updateState sets the desired state of the devbox.
+func (d *Devbox) updateState(ctx context.Context, state v1alpha2.DevboxState) error {
+	err := d.sdk.client.UpdateState(ctx, d.crd.Name, state)
+	d.sdk.logState(ctx, d.crd.Namespace, d.crd.Name, state, err)
+	d.sdk.metrics.recordTransition(state, err)
+	return err
+}
+
+// ToggleState pauses a running devbox and starts a paused or stopped one.
+func (d *Devbox) ToggleState(ctx context.Context) error {
+	if err := d.RefreshInfo(ctx); err != nil {
 		return err
 	}
-	d.sdk.cache.Delete(d.crd.Name)
-	return nil
+
+	switch d.crd.Status.Phase {
+	case v1alpha2.DevboxPhaseRunning:
+		return d.Pause(ctx)
+	case v1alpha2.DevboxPhasePaused, v1alpha2.DevboxPhaseStopped:
+		return d.Start(ctx)
+	case v1alpha2.DevboxPhaseShutdown:
+		return ErrCannotToggleShutdown
+	default:
+		return errors.New("cannot toggle devbox in phase " + string(d.crd.Status.Phase))
+	}
 }
 
-// TimeoutError represents a timeout error.
-type TimeoutError struct {
-	message string
-	timeout time.Duration
+// Delete deletes the devbox. It returns ErrDevboxLocked if the devbox is
+// locked.
+func (d *Devbox) Delete(ctx context.Context) error {
+	return d.sdk.deleteDevbox(ctx, d.crd.Name)
 }
 
-func (e *TimeoutError) Error() string {
-	return e.message + " (timeout: " + e.timeout.String() + ")"
+// deleteDevbox deletes the named devbox unless it is locked. The lock is read
+// from the API server rather than the cache, so that a lock set by another
+// client is honoured.
+func (s *DevboxSDK) deleteDevbox(ctx context.Context, name string) error {
+	crd, err := s.client.Get(ctx, name)
+	if err != nil {
+		return apiError(err)
+	}
+	if crd.Annotations[annotationLocked] == "true" {
+		return ErrDevboxLocked
+	}
+	if err := s.client.Delete(ctx, name); err != nil {
+		return apiError(err)
+	}
+	s.cache.Delete(name)
+	return nil
 }
 
 // SSHKeyPair contains SSH key pair data.
@@ -257,18 +356,6 @@ func (d *Devbox) GetSSHKeyPair(ctx context.Context) (*SSHKeyPair, error) {
 	}, nil
 }
 
-// SSHConnectionString returns the SSH connection string.
-func (d *Devbox) SSHConnectionString() string {
-	switch d.crd.Status.Network.Type {
-	case v1alpha2.NetworkTypeSSHGate:
-		return d.crd.Status.Network.UniqueID + "@bja.sealos.run"
-	case v1alpha2.NetworkTypeNodePort:
-		return d.crd.Spec.Config.User + "@<node-ip>:" + string(rune(d.crd.Status.Network.NodePort))
-	default:
-		return ""
-	}
-}
-
 // Release represents a devbox release.
 type Release struct {
 	crd *v1alpha2.DevBoxRelease
@@ -305,22 +392,41 @@ func (r *Release) TargetImage() string {
 	return r.crd.Status.TargetImage
 }
 
+// Image returns the image reference given as ReleaseConfig.Image when the
+// release was created, or "" if there was none.
+func (r *Release) Image() string {
+	return r.crd.Annotations[annotationReleaseImage]
+}
+
 // CRD returns the underlying CRD object.
 func (r *Release) CRD() *v1alpha2.DevBoxRelease {
 	return r.crd
 }
 
+// annotationReleaseImage records on a release the image it was created for.
+const annotationReleaseImage = "devbox.sealos.run/release-image"
+
 // ReleaseConfig contains configuration for creating a release.
 type ReleaseConfig struct {
 	Version                 string
 	Notes                   string
 	StartDevboxAfterRelease bool
+	// Image, if set, is the reference of an image built and pushed for the
+	// release, as by BuildAndPush. It is recorded in the release
+	// annotations.
+	Image string
 }
 
 // CreateRelease creates a new release for this devbox.
-func (d *Devbox) CreateRelease(ctx context.Context, cfg ReleaseConfig) (*Release, error) {
+func (d *Devbox) CreateRelease(ctx context.Context, cfg ReleaseConfig) (_ *Release, err error) {
+	ctx, span := d.startSpan(ctx, "devbox.CreateRelease", attribute.String("devbox.release.version", cfg.Version))
+	defer func() { endSpan(span, err) }()
+
 	release := &v1alpha2.DevBoxRelease{}
 	release.Name = d.crd.Name + "-" + cfg.Version
+	if cfg.Image != "" {
+		release.Annotations = map[string]string{annotationReleaseImage: cfg.Image}
+	}
 	release.Spec = v1alpha2.DevBoxReleaseSpec{
 		DevboxName:              d.crd.Name,
 		Version:                 cfg.Version,
@@ -0,0 +1,92 @@
+package devbox
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gitlayzer/devbox-sdk-go/api/v1alpha2"
+)
+
+// CloneOptions configures Devbox.Clone.
+type CloneOptions struct {
+	// FromLatestRelease starts the clone from the target image of the newest
+	// completed release instead of the image in the devbox spec, so it
+	// includes everything committed to that release.
+	FromLatestRelease bool
+	// Labels are added to the labels copied from the devbox, overriding
+	// values with the same key.
+	Labels map[string]string
+}
+
+// Clone creates a new devbox named newName in the same namespace with the
+// spec, labels and annotations of this one. Runtime status is not copied, nor
+// are the annotations MirrorDevbox drops, and the clone is recorded in its
+// provenance.
+func (d *Devbox) Clone(ctx context.Context, newName string, opts CloneOptions) (*Devbox, error) {
+	if err := d.RefreshInfo(ctx); err != nil {
+		return nil, err
+	}
+
+	clone := &v1alpha2.Devbox{}
+	clone.Name = newName
+	clone.Namespace = d.crd.Namespace
+	clone.Labels = copyStringMap(d.crd.Labels)
+	clone.Annotations = configAnnotations(d.crd.Annotations)
+	clone.Spec = *d.crd.Spec.DeepCopy()
+
+	if opts.FromLatestRelease {
+		release, err := d.latestRelease(ctx)
+		if err != nil {
+			return nil, err
+		}
+		clone.Spec.Image = release.TargetImage()
+	}
+	if len(opts.Labels) > 0 && clone.Labels == nil {
+		clone.Labels = map[string]string{}
+	}
+	for k, v := range opts.Labels {
+		clone.Labels[k] = v
+	}
+
+	provenance, err := d.sdk.appendProvenance(d.crd.Annotations, ProvenanceEvent{
+		Action:        "Clone",
+		SourceDevbox:  d.crd.Namespace + "/" + d.crd.Name,
+		SourceVersion: d.crd.ResourceVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if clone.Annotations == nil {
+		clone.Annotations = map[string]string{}
+	}
+	clone.Annotations[annotationProvenance] = provenance
+
+	created, err := d.sdk.client.Create(ctx, clone)
+	d.sdk.metrics.recordCreation(err)
+	if err != nil {
+		return nil, apiError(err)
+	}
+	d.sdk.cache.Set(created.Name, created)
+	return newDevbox(created, d.sdk), nil
+}
+
+// latestRelease returns the newest completed release of the devbox.
+func (d *Devbox) latestRelease(ctx context.Context) (*Release, error) {
+	releases, err := d.ListReleases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var latest *Release
+	for _, r := range releases {
+		if r.crd.Status.Phase != v1alpha2.DevBoxReleasePhaseReleased {
+			continue
+		}
+		if latest == nil || r.crd.CreationTimestamp.After(latest.crd.CreationTimestamp.Time) {
+			latest = r
+		}
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("devbox %s: %w", d.crd.Name, ErrNoRelease)
+	}
+	return latest, nil
+}
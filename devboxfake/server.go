@@ -0,0 +1,674 @@
+// Package devboxfake provides an in-memory stand-in for the devbox API of a
+// Kubernetes cluster, for unit-testing code built on the devbox SDK without a
+// live cluster.
+//
+// A Server speaks the Kubernetes REST protocol for devbox resources, so the
+// real SDK client works against it unchanged:
+//
+//	srv := devboxfake.NewServer()
+//	defer srv.Close()
+//	c, err := client.New(srv.Config(), devboxfake.Namespace)
+//
+// The SDK is pointed at the server the same way, by passing srv.Config() as
+// its rest config.
+//
+// Devboxes can be created, fetched, listed with label and field selectors,
+// patched, watched and deleted. A minimal controller sets the phase of a
+// devbox to match its desired state whenever that state changes; use
+// SetPhase to simulate other transitions. Other resources, such as pods,
+// services and events, are not served and yield NotFound errors.
+package devboxfake
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/yaml"
+
+	"github.com/gitlayzer/devbox-sdk-go/api/v1alpha2"
+)
+
+// Namespace is a conventional namespace for tests. The server accepts any
+// namespace.
+const Namespace = "default"
+
+const resource = "devboxes"
+
+var groupResource = v1alpha2.GroupVersion.WithResource(resource).GroupResource()
+
+type key struct {
+	namespace, name string
+}
+
+// Server is an in-memory devbox API server. It is safe for concurrent use.
+type Server struct {
+	srv *httptest.Server
+
+	mu       sync.Mutex
+	devboxes map[key]*v1alpha2.Devbox
+	version  int64
+	watchers map[*watcher]struct{}
+	// sent holds the resource versions sent with each update and patch.
+	sent map[key][]string
+}
+
+// NewServer starts a Server. It must be closed with Close.
+func NewServer(devboxes ...*v1alpha2.Devbox) *Server {
+	s := &Server{
+		devboxes: make(map[key]*v1alpha2.Devbox),
+		watchers: make(map[*watcher]struct{}),
+		sent:     make(map[key][]string),
+	}
+	for _, d := range devboxes {
+		s.Add(d)
+	}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Config returns a rest config for connecting to the server.
+func (s *Server) Config() *rest.Config {
+	return &rest.Config{Host: s.srv.URL}
+}
+
+// Close stops the server and ends all watches.
+func (s *Server) Close() {
+	s.mu.Lock()
+	for w := range s.watchers {
+		w.stop()
+	}
+	s.mu.Unlock()
+	s.srv.Close()
+}
+
+// Add stores a copy of d as if it had been created through the API,
+// replacing any devbox of the same name. An empty namespace means Namespace.
+func (s *Server) Add(d *v1alpha2.Devbox) {
+	d = d.DeepCopy()
+	if d.Namespace == "" {
+		d.Namespace = Namespace
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.initialize(d)
+	if d.Status.Phase == "" {
+		reconcile(d)
+	}
+	s.store(d, watch.Added)
+}
+
+// Get returns a copy of the stored devbox, or nil if there is none.
+func (s *Server) Get(namespace, name string) *v1alpha2.Devbox {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if d, ok := s.devboxes[key{namespace, name}]; ok {
+		return d.DeepCopy()
+	}
+	return nil
+}
+
+// ResourceVersions returns the resource versions that updates and patches of
+// a devbox were sent with, oldest first, with "" for a request without one.
+// Rejected requests are included.
+func (s *Server) ResourceVersions(namespace, name string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.sent[key{namespace, name}]...)
+}
+
+// SetPhase sets the observed phase of a devbox, as the devbox controller
+// would, and notifies watchers.
+func (s *Server) SetPhase(namespace, name string, phase v1alpha2.DevboxPhase) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.devboxes[key{namespace, name}]
+	if !ok {
+		return apierrors.NewNotFound(groupResource, name)
+	}
+	d = d.DeepCopy()
+	d.Status.Phase = phase
+	s.store(d, watch.Modified)
+	return nil
+}
+
+// reconcile sets the phase of d to match its desired state, as the devbox
+// controller eventually does.
+func reconcile(d *v1alpha2.Devbox) {
+	d.Status.State = d.Spec.State
+	switch d.Spec.State {
+	case v1alpha2.DevboxStateRunning, "":
+		d.Status.Phase = v1alpha2.DevboxPhaseRunning
+	case v1alpha2.DevboxStatePaused:
+		d.Status.Phase = v1alpha2.DevboxPhasePaused
+	case v1alpha2.DevboxStateStopped:
+		d.Status.Phase = v1alpha2.DevboxPhaseStopped
+	case v1alpha2.DevboxStateShutdown:
+		d.Status.Phase = v1alpha2.DevboxPhaseShutdown
+	}
+}
+
+// initialize fills in the server-assigned metadata of a new devbox.
+func (s *Server) initialize(d *v1alpha2.Devbox) {
+	d.APIVersion = v1alpha2.GroupVersion.String()
+	d.Kind = "Devbox"
+	if d.UID == "" {
+		d.UID = uuid.NewUUID()
+	}
+	if d.CreationTimestamp.IsZero() {
+		d.CreationTimestamp = metav1.NewTime(time.Now())
+	}
+	d.Generation = 1
+}
+
+// store saves d with a new resource version and notifies watchers. The
+// caller must hold s.mu.
+func (s *Server) store(d *v1alpha2.Devbox, eventType watch.EventType) {
+	s.version++
+	d.ResourceVersion = strconv.FormatInt(s.version, 10)
+	if eventType == watch.Deleted {
+		delete(s.devboxes, key{d.Namespace, d.Name})
+	} else {
+		s.devboxes[key{d.Namespace, d.Name}] = d
+	}
+	for w := range s.watchers {
+		w.send(eventType, d)
+	}
+}
+
+// request is a parsed devbox API path.
+type request struct {
+	namespace   string // empty for all namespaces
+	name        string // empty for collections
+	subresource string
+}
+
+// parsePath parses paths of the form
+// /apis/<group>/<version>[/namespaces/<ns>]/devboxes[/<name>[/status]].
+func parsePath(path string) (request, bool) {
+	prefix := "/apis/" + v1alpha2.GroupVersion.String() + "/"
+	rest, ok := strings.CutPrefix(path, prefix)
+	if !ok {
+		return request{}, false
+	}
+	parts := strings.Split(strings.Trim(rest, "/"), "/")
+
+	var r request
+	if len(parts) >= 2 && parts[0] == "namespaces" {
+		r.namespace = parts[1]
+		parts = parts[2:]
+	}
+	if len(parts) == 0 || parts[0] != resource || len(parts) > 3 {
+		return request{}, false
+	}
+	if len(parts) > 1 {
+		r.name = parts[1]
+	}
+	if len(parts) > 2 {
+		r.subresource = parts[2]
+		if r.subresource != "status" {
+			return request{}, false
+		}
+	}
+	if r.name != "" && r.namespace == "" {
+		return request{}, false
+	}
+	return r, true
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	r, ok := parsePath(req.URL.Path)
+	if !ok {
+		writeError(w, apierrors.NewNotFound(groupResource, req.URL.Path))
+		return
+	}
+	query := req.URL.Query()
+	dryRun := query.Get("dryRun") == metav1.DryRunAll
+
+	switch {
+	case req.Method == http.MethodGet && r.name == "" && query.Get("watch") == "true":
+		s.watch(w, req, r.namespace)
+	case req.Method == http.MethodGet && r.name == "":
+		s.list(w, r.namespace, query.Get("labelSelector"), query.Get("fieldSelector"), query.Get("limit"), query.Get("continue"))
+	case req.Method == http.MethodGet:
+		s.get(w, r)
+	case req.Method == http.MethodPost && r.name == "" && r.namespace != "":
+		s.create(w, req, r.namespace, dryRun)
+	case req.Method == http.MethodPut && r.name != "":
+		s.update(w, req, r, dryRun)
+	case req.Method == http.MethodPatch && r.name != "":
+		s.patch(w, req, r, query.Get("fieldManager"), dryRun)
+	case req.Method == http.MethodDelete && r.name != "":
+		s.delete(w, r, dryRun)
+	default:
+		writeError(w, apierrors.NewMethodNotSupported(groupResource, req.Method))
+	}
+}
+
+func (s *Server) get(w http.ResponseWriter, r request) {
+	d := s.Get(r.namespace, r.name)
+	if d == nil {
+		writeError(w, apierrors.NewNotFound(groupResource, r.name))
+		return
+	}
+	writeJSON(w, http.StatusOK, d)
+}
+
+// list serves a collection, sorted by namespace and name. The continue token
+// is the namespace and name of the last item of the previous page.
+func (s *Server) list(w http.ResponseWriter, namespace, labelSelector, fieldSelector, limit, cont string) {
+	matches, err := matcher(labelSelector, fieldSelector)
+	if err != nil {
+		writeError(w, apierrors.NewBadRequest(err.Error()))
+		return
+	}
+	pageSize, _ := strconv.Atoi(limit)
+
+	s.mu.Lock()
+	list := &v1alpha2.DevboxList{
+		TypeMeta: metav1.TypeMeta{APIVersion: v1alpha2.GroupVersion.String(), Kind: "DevboxList"},
+		ListMeta: metav1.ListMeta{ResourceVersion: strconv.FormatInt(s.version, 10)},
+		Items:    []v1alpha2.Devbox{},
+	}
+	for k, d := range s.devboxes {
+		if (namespace == "" || k.namespace == namespace) && matches(d) {
+			list.Items = append(list.Items, *d.DeepCopy())
+		}
+	}
+	s.mu.Unlock()
+
+	sort.Slice(list.Items, func(i, j int) bool {
+		return continueToken(&list.Items[i]) < continueToken(&list.Items[j])
+	})
+	if cont != "" {
+		start := sort.Search(len(list.Items), func(i int) bool {
+			return continueToken(&list.Items[i]) > cont
+		})
+		list.Items = list.Items[start:]
+	}
+	if pageSize > 0 && len(list.Items) > pageSize {
+		remaining := int64(len(list.Items) - pageSize)
+		list.Items = list.Items[:pageSize]
+		list.Continue = continueToken(&list.Items[pageSize-1])
+		list.RemainingItemCount = &remaining
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func continueToken(d *v1alpha2.Devbox) string {
+	return d.Namespace + "/" + d.Name
+}
+
+// matcher returns a predicate for the given selectors. Field selectors
+// support metadata.name, metadata.namespace, status.phase and status.node.
+func matcher(labelSelector, fieldSelector string) (func(*v1alpha2.Devbox) bool, error) {
+	ls, err := labels.Parse(labelSelector)
+	if err != nil {
+		return nil, err
+	}
+	fs, err := fields.ParseSelector(fieldSelector)
+	if err != nil {
+		return nil, err
+	}
+	for _, req := range fs.Requirements() {
+		switch req.Field {
+		case "metadata.name", "metadata.namespace", "status.phase", "status.node":
+		default:
+			return nil, fmt.Errorf("field label not supported: %s", req.Field)
+		}
+	}
+	return func(d *v1alpha2.Devbox) bool {
+		return ls.Matches(labels.Set(d.Labels)) && fs.Matches(fields.Set{
+			"metadata.name":      d.Name,
+			"metadata.namespace": d.Namespace,
+			"status.phase":       string(d.Status.Phase),
+			"status.node":        d.Status.Node,
+		})
+	}, nil
+}
+
+func (s *Server) create(w http.ResponseWriter, req *http.Request, namespace string, dryRun bool) {
+	d := &v1alpha2.Devbox{}
+	if err := decodeBody(req, d); err != nil {
+		writeError(w, err)
+		return
+	}
+	if d.Name == "" && d.GenerateName != "" {
+		d.Name = d.GenerateName + strings.ToLower(string(uuid.NewUUID())[:5])
+	}
+	if d.Name == "" {
+		writeError(w, apierrors.NewBadRequest("name is required"))
+		return
+	}
+	d.Namespace = namespace
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.devboxes[key{namespace, d.Name}]; ok {
+		writeError(w, apierrors.NewAlreadyExists(groupResource, d.Name))
+		return
+	}
+	d.UID, d.CreationTimestamp = "", metav1.Time{}
+	s.initialize(d)
+	reconcile(d)
+	if !dryRun {
+		s.store(d, watch.Added)
+	}
+	writeJSON(w, http.StatusCreated, d)
+}
+
+func (s *Server) update(w http.ResponseWriter, req *http.Request, r request, dryRun bool) {
+	d := &v1alpha2.Devbox{}
+	if err := decodeBody(req, d); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k := key{r.namespace, r.name}
+	s.sent[k] = append(s.sent[k], d.ResourceVersion)
+	current, ok := s.devboxes[k]
+	if !ok {
+		writeError(w, apierrors.NewNotFound(groupResource, r.name))
+		return
+	}
+	if d.ResourceVersion != "" && d.ResourceVersion != current.ResourceVersion {
+		writeError(w, conflict(r.name))
+		return
+	}
+	s.replace(w, current, d, r.subresource, dryRun)
+}
+
+// patch applies JSON merge patches and server-side apply patches, both
+// treated as RFC 7386 merge patches. A resource version in the patch is a
+// precondition, as on a real API server.
+func (s *Server) patch(w http.ResponseWriter, req *http.Request, r request, fieldManager string, dryRun bool) {
+	contentType := req.Header.Get("Content-Type")
+	apply := contentType == string(k8stypes.ApplyPatchType)
+	if !apply && contentType != string(k8stypes.MergePatchType) {
+		writeError(w, apierrors.NewGenericServerResponse(http.StatusUnsupportedMediaType, "patch", groupResource, r.name,
+			"only merge and apply patches are supported", 0, false))
+		return
+	}
+	if apply && fieldManager == "" {
+		writeError(w, apierrors.NewBadRequest("fieldManager is required for apply patches"))
+		return
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		writeError(w, apierrors.NewBadRequest(err.Error()))
+		return
+	}
+	if apply {
+		if body, err = yaml.YAMLToJSON(body); err != nil {
+			writeError(w, apierrors.NewBadRequest(err.Error()))
+			return
+		}
+	}
+	var patch interface{}
+	if err := json.Unmarshal(body, &patch); err != nil {
+		writeError(w, apierrors.NewBadRequest(err.Error()))
+		return
+	}
+
+	var sent struct {
+		Metadata struct {
+			ResourceVersion string `json:"resourceVersion"`
+		} `json:"metadata"`
+	}
+	json.Unmarshal(body, &sent)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k := key{r.namespace, r.name}
+	s.sent[k] = append(s.sent[k], sent.Metadata.ResourceVersion)
+	current, ok := s.devboxes[k]
+	if !ok && !apply {
+		writeError(w, apierrors.NewNotFound(groupResource, r.name))
+		return
+	}
+	if !ok {
+		d := &v1alpha2.Devbox{}
+		if err := json.Unmarshal(body, d); err != nil {
+			writeError(w, apierrors.NewBadRequest(err.Error()))
+			return
+		}
+		d.Name, d.Namespace = r.name, r.namespace
+		s.initialize(d)
+		reconcile(d)
+		if !dryRun {
+			s.store(d, watch.Added)
+		}
+		writeJSON(w, http.StatusCreated, d)
+		return
+	}
+
+	var target interface{}
+	data, _ := json.Marshal(current)
+	json.Unmarshal(data, &target)
+	if data, err = json.Marshal(mergePatch(target, patch)); err != nil {
+		writeError(w, apierrors.NewInternalError(err))
+		return
+	}
+	d := &v1alpha2.Devbox{}
+	if err := json.Unmarshal(data, d); err != nil {
+		writeError(w, apierrors.NewBadRequest(err.Error()))
+		return
+	}
+	if d.ResourceVersion != current.ResourceVersion {
+		writeError(w, conflict(r.name))
+		return
+	}
+	s.replace(w, current, d, r.subresource, dryRun)
+}
+
+// replace stores d in place of current, keeping the server-assigned metadata
+// and, depending on subresource, either the spec or the status of current.
+// The caller must hold s.mu.
+func (s *Server) replace(w http.ResponseWriter, current, d *v1alpha2.Devbox, subresource string, dryRun bool) {
+	d.TypeMeta = current.TypeMeta
+	d.Name, d.Namespace = current.Name, current.Namespace
+	d.UID, d.CreationTimestamp = current.UID, current.CreationTimestamp
+	d.Generation = current.Generation
+
+	if subresource == "status" {
+		d.Spec = current.Spec
+	} else {
+		d.Status = current.Status
+		if !jsonEqual(d.Spec, current.Spec) {
+			d.Generation++
+		}
+		if d.Spec.State != current.Spec.State {
+			reconcile(d)
+		}
+	}
+	if dryRun {
+		d.ResourceVersion = current.ResourceVersion
+	} else {
+		s.store(d, watch.Modified)
+	}
+	writeJSON(w, http.StatusOK, d)
+}
+
+func (s *Server) delete(w http.ResponseWriter, r request, dryRun bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.devboxes[key{r.namespace, r.name}]
+	if !ok {
+		writeError(w, apierrors.NewNotFound(groupResource, r.name))
+		return
+	}
+	if !dryRun {
+		s.store(d.DeepCopy(), watch.Deleted)
+	}
+	writeJSON(w, http.StatusOK, &metav1.Status{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Status"},
+		Status:   metav1.StatusSuccess,
+		Details:  &metav1.StatusDetails{Name: r.name, Group: groupResource.Group, Kind: resource, UID: d.UID},
+	})
+}
+
+// watcher is an open watch request.
+type watcher struct {
+	namespace string
+	matches   func(*v1alpha2.Devbox) bool
+	events    chan metav1.WatchEvent
+	done      chan struct{}
+	once      sync.Once
+}
+
+func (w *watcher) stop() {
+	w.once.Do(func() { close(w.done) })
+}
+
+// send queues an event for d if it matches. A watcher that falls too far
+// behind is stopped; clients are expected to watch again.
+func (w *watcher) send(eventType watch.EventType, d *v1alpha2.Devbox) {
+	if (w.namespace != "" && d.Namespace != w.namespace) || !w.matches(d) {
+		return
+	}
+	data, err := json.Marshal(d)
+	if err != nil {
+		return
+	}
+	select {
+	case w.events <- metav1.WatchEvent{Type: string(eventType), Object: runtime.RawExtension{Raw: data}}:
+	default:
+		w.stop()
+	}
+}
+
+// watch streams changes from the time of the request; the requested resource
+// version is ignored.
+func (s *Server) watch(rw http.ResponseWriter, req *http.Request, namespace string) {
+	query := req.URL.Query()
+	matches, err := matcher(query.Get("labelSelector"), query.Get("fieldSelector"))
+	if err != nil {
+		writeError(rw, apierrors.NewBadRequest(err.Error()))
+		return
+	}
+	w := &watcher{
+		namespace: namespace,
+		matches:   matches,
+		events:    make(chan metav1.WatchEvent, 256),
+		done:      make(chan struct{}),
+	}
+	s.mu.Lock()
+	s.watchers[w] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.watchers, w)
+		s.mu.Unlock()
+	}()
+
+	timeout := time.Duration(0)
+	if seconds, err := strconv.Atoi(query.Get("timeoutSeconds")); err == nil && seconds > 0 {
+		timeout = time.Duration(seconds) * time.Second
+	}
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	rw.Header().Set("Transfer-Encoding", "chunked")
+	rw.WriteHeader(http.StatusOK)
+	flusher, _ := rw.(http.Flusher)
+	if flusher != nil {
+		flusher.Flush()
+	}
+	encoder := json.NewEncoder(rw)
+	for {
+		select {
+		case <-req.Context().Done():
+			return
+		case <-w.done:
+			return
+		case <-expired:
+			return
+		case event := <-w.events:
+			if err := encoder.Encode(&event); err != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+func conflict(name string) error {
+	return apierrors.NewConflict(groupResource, name,
+		errors.New("the object has been modified; please apply your changes to the latest version and try again"))
+}
+
+func decodeBody(req *http.Request, v interface{}) error {
+	if err := json.NewDecoder(req.Body).Decode(v); err != nil {
+		return apierrors.NewBadRequest(err.Error())
+	}
+	return nil
+}
+
+// mergePatch applies an RFC 7386 JSON merge patch to target.
+func mergePatch(target, patch interface{}) interface{} {
+	patchMap, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetMap, ok := target.(map[string]interface{})
+	if !ok {
+		targetMap = make(map[string]interface{})
+	}
+	for k, v := range patchMap {
+		if v == nil {
+			delete(targetMap, k)
+		} else {
+			targetMap[k] = mergePatch(targetMap[k], v)
+		}
+	}
+	return targetMap
+}
+
+func jsonEqual(a, b interface{}) bool {
+	x, _ := json.Marshal(a)
+	y, _ := json.Marshal(b)
+	return string(x) == string(y)
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) {
+		status = apierrors.NewInternalError(err)
+	}
+	s := status.Status()
+	s.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "Status"}
+	writeJSON(w, int(s.Code), &s)
+}
@@ -0,0 +1,108 @@
+package devbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	sigsyaml "sigs.k8s.io/yaml"
+
+	"github.com/gitlayzer/devbox-sdk-go/api/v1alpha2"
+)
+
+// ManifestFormat is the encoding of a devbox manifest.
+type ManifestFormat string
+
+const (
+	ManifestYAML ManifestFormat = "yaml"
+	ManifestJSON ManifestFormat = "json"
+)
+
+// serverMetadataFields are the metadata fields the API server populates,
+// which have no place in a manifest kept in version control.
+var serverMetadataFields = []string{
+	"uid",
+	"resourceVersion",
+	"generation",
+	"creationTimestamp",
+	"deletionTimestamp",
+	"deletionGracePeriodSeconds",
+	"managedFields",
+	"selfLink",
+	"ownerReferences",
+}
+
+// ExportManifest returns the devbox as a Kubernetes manifest in format,
+// without its status, the metadata populated by the API server and the
+// annotations MirrorDevbox drops, so that it can be kept in version control
+// and recreated with CreateDevboxFromManifest.
+func (d *Devbox) ExportManifest(format ManifestFormat) ([]byte, error) {
+	crd := d.crd.DeepCopy()
+	crd.APIVersion = v1alpha2.GroupVersion.String()
+	crd.Kind = "Devbox"
+
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(crd)
+	if err != nil {
+		return nil, err
+	}
+	unstructured.RemoveNestedField(obj, "status")
+	for _, field := range serverMetadataFields {
+		unstructured.RemoveNestedField(obj, "metadata", field)
+	}
+	for _, key := range runtimeAnnotations {
+		unstructured.RemoveNestedField(obj, "metadata", "annotations", key)
+	}
+	if annotations, _, _ := unstructured.NestedMap(obj, "metadata", "annotations"); len(annotations) == 0 {
+		unstructured.RemoveNestedField(obj, "metadata", "annotations")
+	}
+
+	switch format {
+	case ManifestYAML:
+		return sigsyaml.Marshal(obj)
+	case ManifestJSON:
+		return json.MarshalIndent(obj, "", "  ")
+	default:
+		return nil, fmt.Errorf("unknown manifest format %q", format)
+	}
+}
+
+// CreateDevboxFromManifest creates a devbox from a YAML or JSON manifest of a
+// single Devbox, as written by ExportManifest. The manifest may omit the
+// namespace; if it names one, it must be the SDK namespace. Status,
+// server-populated metadata and runtime annotations in the manifest are
+// ignored.
+func (s *DevboxSDK) CreateDevboxFromManifest(ctx context.Context, manifest []byte) (*Devbox, error) {
+	crd := &v1alpha2.Devbox{}
+	if err := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(manifest), 4096).Decode(crd); err != nil {
+		return nil, fmt.Errorf("decoding devbox manifest: %w", err)
+	}
+	if crd.APIVersion != v1alpha2.GroupVersion.String() || crd.Kind != "Devbox" {
+		return nil, fmt.Errorf("manifest is a %s %s, not a %s Devbox", crd.APIVersion, crd.Kind, v1alpha2.GroupVersion)
+	}
+	if crd.Name == "" {
+		return nil, errors.New("manifest has no metadata.name")
+	}
+	if crd.Namespace != "" && crd.Namespace != s.namespace {
+		return nil, fmt.Errorf("manifest is for namespace %s, but the SDK is bound to %s", crd.Namespace, s.namespace)
+	}
+
+	fresh := &v1alpha2.Devbox{}
+	fresh.Name = crd.Name
+	fresh.Namespace = s.namespace
+	fresh.Labels = crd.Labels
+	fresh.Annotations = configAnnotations(crd.Annotations)
+	fresh.Spec = crd.Spec
+
+	created, err := s.client.Create(ctx, fresh)
+	s.metrics.recordCreation(err)
+	if err != nil {
+		return nil, apiError(err)
+	}
+	s.cache.Set(created.Name, created)
+	return newDevbox(created, s), nil
+}
@@ -0,0 +1,30 @@
+package devbox
+
+import (
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+
+	"github.com/gitlayzer/devbox-sdk-go/api/v1alpha2"
+	"github.com/gitlayzer/devbox-sdk-go/client"
+)
+
+// devboxResource is the devbox resource, as addressed by the dynamic client.
+var devboxResource = v1alpha2.GroupVersion.WithResource("devboxes")
+
+// newClients builds the clients of an SDK from its rest config: the typed
+// client, bound to namespace, and the dynamic client kept next to it. The
+// typed client cannot reach other namespaces or server-side apply, so the few
+// calls that need either go through the dynamic client. The SDK builds both
+// once its options are applied; copies of it build new ones only when their
+// rest config changes, and a new typed client only when their namespace does.
+func newClients(cfg *rest.Config, namespace string) (*client.Client, dynamic.Interface, error) {
+	c, err := client.New(cfg, namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+	dc, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c, dc, nil
+}
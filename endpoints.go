@@ -0,0 +1,86 @@
+package devbox
+
+import (
+	"context"
+
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// EndpointConditions is the state of a service endpoint. Unknown conditions
+// are reported as false, except Ready and Serving which Kubernetes defines as
+// true when unknown.
+type EndpointConditions struct {
+	Ready       bool
+	Serving     bool
+	Terminating bool
+}
+
+// EndpointSlice is one endpoint of a devbox Service: the addresses of a pod
+// and the ports it serves.
+type EndpointSlice struct {
+	Addresses  []string
+	Ports      []int32
+	Conditions EndpointConditions
+}
+
+// GetServiceEndpoints returns the endpoints of the Services selecting the
+// pod of the named devbox, read from their discovery.k8s.io/v1 EndpointSlices.
+// It returns ErrNoEndpoints if there are none, e.g. because the pod is not
+// scheduled yet.
+func (s *DevboxSDK) GetServiceEndpoints(ctx context.Context, devboxName string) ([]EndpointSlice, error) {
+	return serviceEndpoints(ctx, s.client.Clientset(), s.namespace, devboxName)
+}
+
+func serviceEndpoints(ctx context.Context, clientset kubernetes.Interface, namespace, devboxName string) ([]EndpointSlice, error) {
+	services, err := clientset.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var endpoints []EndpointSlice
+	for _, svc := range services.Items {
+		if svc.Spec.Selector[labelAppName] != devboxName {
+			continue
+		}
+		slices, err := clientset.DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: labels.SelectorFromSet(labels.Set{discoveryv1.LabelServiceName: svc.Name}).String(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, slice := range slices.Items {
+			endpoints = append(endpoints, sliceEndpoints(&slice)...)
+		}
+	}
+
+	if len(endpoints) == 0 {
+		return nil, ErrNoEndpoints
+	}
+	return endpoints, nil
+}
+
+func sliceEndpoints(slice *discoveryv1.EndpointSlice) []EndpointSlice {
+	var ports []int32
+	for _, p := range slice.Ports {
+		if p.Port != nil {
+			ports = append(ports, *p.Port)
+		}
+	}
+
+	endpoints := make([]EndpointSlice, len(slice.Endpoints))
+	for i, e := range slice.Endpoints {
+		endpoints[i] = EndpointSlice{
+			Addresses: e.Addresses,
+			Ports:     ports,
+			Conditions: EndpointConditions{
+				Ready:       e.Conditions.Ready == nil || *e.Conditions.Ready,
+				Serving:     e.Conditions.Serving == nil || *e.Conditions.Serving,
+				Terminating: e.Conditions.Terminating != nil && *e.Conditions.Terminating,
+			},
+		}
+	}
+	return endpoints
+}
@@ -0,0 +1,102 @@
+package devbox
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestServiceEndpoints(t *testing.T) {
+	service := func(name, app string) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"},
+			Spec:       corev1.ServiceSpec{Selector: map[string]string{labelAppName: app}},
+		}
+	}
+	slice := func(name, service string, endpoints ...discoveryv1.Endpoint) *discoveryv1.EndpointSlice {
+		return &discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "ns",
+				Labels:    map[string]string{discoveryv1.LabelServiceName: service},
+			},
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Ports:       []discoveryv1.EndpointPort{{Port: ptrTo[int32](8080)}, {Port: nil}},
+			Endpoints:   endpoints,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		objects []runtime.Object
+		want    []EndpointSlice
+		wantErr error
+	}{
+		{
+			name: "ready and terminating endpoints",
+			objects: []runtime.Object{
+				service("alice", "alice"),
+				slice("alice-abc", "alice",
+					discoveryv1.Endpoint{Addresses: []string{"10.0.0.1"}},
+					discoveryv1.Endpoint{
+						Addresses: []string{"10.0.0.2"},
+						Conditions: discoveryv1.EndpointConditions{
+							Ready:       ptrTo(false),
+							Serving:     ptrTo(true),
+							Terminating: ptrTo(true),
+						},
+					},
+				),
+			},
+			want: []EndpointSlice{
+				{Addresses: []string{"10.0.0.1"}, Ports: []int32{8080}, Conditions: EndpointConditions{Ready: true, Serving: true}},
+				{Addresses: []string{"10.0.0.2"}, Ports: []int32{8080}, Conditions: EndpointConditions{Serving: true, Terminating: true}},
+			},
+		},
+		{
+			name: "other devboxes are ignored",
+			objects: []runtime.Object{
+				service("alice", "alice"),
+				service("bob", "bob"),
+				slice("alice-abc", "alice", discoveryv1.Endpoint{Addresses: []string{"10.0.0.1"}}),
+				slice("bob-abc", "bob", discoveryv1.Endpoint{Addresses: []string{"10.0.0.9"}}),
+			},
+			want: []EndpointSlice{
+				{Addresses: []string{"10.0.0.1"}, Ports: []int32{8080}, Conditions: EndpointConditions{Ready: true, Serving: true}},
+			},
+		},
+		{
+			name:    "no service",
+			objects: []runtime.Object{service("bob", "bob")},
+			wantErr: ErrNoEndpoints,
+		},
+		{
+			name:    "no endpoints yet",
+			objects: []runtime.Object{service("alice", "alice"), slice("alice-abc", "alice")},
+			wantErr: ErrNoEndpoints,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset(tt.objects...)
+			got, err := serviceEndpoints(context.Background(), clientset, "ns", "alice")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("serviceEndpoints() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("serviceEndpoints() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func ptrTo[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,142 @@
+package devbox
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+const (
+	labelManaged = "devbox.sealos.run/managed"
+
+	// namespaceDefaultsConfigMap holds the ResourceQuota (key "resourceQuota")
+	// and LimitRange (key "limitRange") manifests applied by EnsureNamespace.
+	namespaceDefaultsConfigMap = "devbox-namespace-defaults"
+	namespaceDefaultsName      = "devbox-defaults"
+)
+
+// EnsureNamespace creates the namespace if it does not exist and marks it as
+// managed by devbox. The default ResourceQuota and LimitRange stored in the
+// devbox-namespace-defaults ConfigMap of the SDK namespace, if present, are
+// applied to it. It returns ErrForbidden if the SDK may not create or label
+// the namespace.
+func (s *DevboxSDK) EnsureNamespace(ctx context.Context, namespace string) error {
+	core := s.client.Clientset().CoreV1()
+
+	// Look before creating, so that an existing namespace needs no create
+	// permission, and one that is already labelled no patch permission.
+	existing, err := core.Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		ns := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   namespace,
+				Labels: map[string]string{labelManaged: "true"},
+			},
+		}
+		existing, err = core.Namespaces().Create(ctx, ns, metav1.CreateOptions{})
+		if apierrors.IsAlreadyExists(err) {
+			// Created concurrently, with unknown labels; patch them below.
+			existing, err = &corev1.Namespace{}, nil
+		}
+	}
+	if err == nil && existing.Labels[labelManaged] != "true" {
+		patch := fmt.Sprintf(`{"metadata":{"labels":{%q:"true"}}}`, labelManaged)
+		_, err = core.Namespaces().Patch(ctx, namespace, k8stypes.MergePatchType, []byte(patch), metav1.PatchOptions{})
+	}
+	if apierrors.IsForbidden(err) {
+		return fmt.Errorf("%w: %w", ErrForbidden, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	defaults, err := core.ConfigMaps(s.namespace).Get(ctx, namespaceDefaultsConfigMap, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if manifest := defaults.Data["resourceQuota"]; manifest != "" {
+		quota := &corev1.ResourceQuota{}
+		if err := decodeManifest(manifest, quota); err != nil {
+			return fmt.Errorf("decode default resource quota: %w", err)
+		}
+		if err := s.applyResourceQuota(ctx, namespace, quota); err != nil {
+			return err
+		}
+	}
+	if manifest := defaults.Data["limitRange"]; manifest != "" {
+		limits := &corev1.LimitRange{}
+		if err := decodeManifest(manifest, limits); err != nil {
+			return fmt.Errorf("decode default limit range: %w", err)
+		}
+		if err := s.applyLimitRange(ctx, namespace, limits); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *DevboxSDK) applyResourceQuota(ctx context.Context, namespace string, quota *corev1.ResourceQuota) error {
+	quotas := s.client.Clientset().CoreV1().ResourceQuotas(namespace)
+	quota.ObjectMeta = defaultsMeta(quota.ObjectMeta, namespace)
+
+	existing, err := quotas.Get(ctx, quota.Name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		_, err = quotas.Create(ctx, quota, metav1.CreateOptions{})
+	case err == nil:
+		existing.Spec = quota.Spec
+		_, err = quotas.Update(ctx, existing, metav1.UpdateOptions{})
+	}
+	if apierrors.IsForbidden(err) {
+		return fmt.Errorf("%w: %w", ErrForbidden, err)
+	}
+	return err
+}
+
+func (s *DevboxSDK) applyLimitRange(ctx context.Context, namespace string, limits *corev1.LimitRange) error {
+	ranges := s.client.Clientset().CoreV1().LimitRanges(namespace)
+	limits.ObjectMeta = defaultsMeta(limits.ObjectMeta, namespace)
+
+	existing, err := ranges.Get(ctx, limits.Name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		_, err = ranges.Create(ctx, limits, metav1.CreateOptions{})
+	case err == nil:
+		existing.Spec = limits.Spec
+		_, err = ranges.Update(ctx, existing, metav1.UpdateOptions{})
+	}
+	if apierrors.IsForbidden(err) {
+		return fmt.Errorf("%w: %w", ErrForbidden, err)
+	}
+	return err
+}
+
+// defaultsMeta keeps only the name and labels of a default manifest and moves
+// it into namespace.
+func defaultsMeta(meta metav1.ObjectMeta, namespace string) metav1.ObjectMeta {
+	name := meta.Name
+	if name == "" {
+		name = namespaceDefaultsName
+	}
+	labels := copyStringMap(meta.Labels)
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[labelManaged] = "true"
+	return metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels}
+}
+
+// decodeManifest decodes a YAML or JSON manifest into obj.
+func decodeManifest(manifest string, obj interface{}) error {
+	return yaml.NewYAMLOrJSONDecoder(bytes.NewReader([]byte(manifest)), 4096).Decode(obj)
+}
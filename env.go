@@ -0,0 +1,82 @@
+package devbox
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// EnvSourceType is the kind of object an EnvSource references.
+type EnvSourceType string
+
+const (
+	EnvSourceSecret    EnvSourceType = "Secret"
+	EnvSourceConfigMap EnvSourceType = "ConfigMap"
+)
+
+// EnvSource is an object whose keys are injected as environment variables.
+type EnvSource struct {
+	Type   EnvSourceType
+	Name   string
+	Prefix string
+}
+
+// InjectEnvFromSecret exposes every key of the named Secret as an environment
+// variable in the devbox, with prefix prepended to each name.
+func (d *Devbox) InjectEnvFromSecret(ctx context.Context, secretName string, prefix string) error {
+	if err := d.RefreshInfo(ctx); err != nil {
+		return err
+	}
+
+	envFrom := d.crd.Spec.Config.EnvFrom
+	for _, src := range envFrom {
+		if src.SecretRef != nil && src.SecretRef.Name == secretName {
+			return fmt.Errorf("secret %q is already injected", secretName)
+		}
+	}
+	envFrom = append(append([]corev1.EnvFromSource{}, envFrom...), corev1.EnvFromSource{
+		Prefix:    prefix,
+		SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: secretName}},
+	})
+
+	return d.patchConfig(ctx, map[string]interface{}{"envFrom": envFrom})
+}
+
+// RemoveEnvFromSecret removes the envFrom entry referencing the named Secret.
+func (d *Devbox) RemoveEnvFromSecret(ctx context.Context, secretName string) error {
+	if err := d.RefreshInfo(ctx); err != nil {
+		return err
+	}
+
+	envFrom := make([]corev1.EnvFromSource, 0, len(d.crd.Spec.Config.EnvFrom))
+	for _, src := range d.crd.Spec.Config.EnvFrom {
+		if src.SecretRef == nil || src.SecretRef.Name != secretName {
+			envFrom = append(envFrom, src)
+		}
+	}
+	if len(envFrom) == len(d.crd.Spec.Config.EnvFrom) {
+		return fmt.Errorf("secret %q is not injected", secretName)
+	}
+
+	return d.patchConfig(ctx, map[string]interface{}{"envFrom": envFrom})
+}
+
+// ListEnvFromSources returns the Secrets and ConfigMaps injected as environment
+// variables.
+func (d *Devbox) ListEnvFromSources(ctx context.Context) ([]EnvSource, error) {
+	if err := d.RefreshInfo(ctx); err != nil {
+		return nil, err
+	}
+
+	var sources []EnvSource
+	for _, src := range d.crd.Spec.Config.EnvFrom {
+		switch {
+		case src.SecretRef != nil:
+			sources = append(sources, EnvSource{Type: EnvSourceSecret, Name: src.SecretRef.Name, Prefix: src.Prefix})
+		case src.ConfigMapRef != nil:
+			sources = append(sources, EnvSource{Type: EnvSourceConfigMap, Name: src.ConfigMapRef.Name, Prefix: src.Prefix})
+		}
+	}
+	return sources, nil
+}
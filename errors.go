@@ -0,0 +1,238 @@
+package devbox
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+var (
+	// ErrDevboxRunning is returned when an operation requires the devbox to be
+	// stopped or paused first.
+	ErrDevboxRunning = errors.New("devbox must be stopped or paused")
+
+	// ErrReleaseNotFinished is returned when a release has not finished building.
+	ErrReleaseNotFinished = errors.New("release has not finished")
+
+	// ErrContainerNotRunning is returned when the devbox container has not started.
+	ErrContainerNotRunning = errors.New("devbox container is not running")
+
+	// ErrPriorityClassNotFound is returned when the requested PriorityClass does
+	// not exist in the cluster.
+	ErrPriorityClassNotFound = errors.New("priority class not found")
+
+	// ErrBandwidthLimitNotSupported is returned when the namespace network plugin
+	// does not enforce bandwidth limits.
+	ErrBandwidthLimitNotSupported = errors.New("bandwidth limits are not supported in this namespace")
+
+	// ErrCannotToggleShutdown is returned by ToggleState for a shut down devbox.
+	ErrCannotToggleShutdown = errors.New("cannot toggle a shut down devbox")
+
+	// ErrQuotaExceeded is returned when a change would exceed the namespace
+	// ResourceQuota. Use errors.As with *QuotaExceededError for details.
+	ErrQuotaExceeded = errors.New("namespace quota exceeded")
+
+	// ErrContainerRunning is returned when the devbox container has not terminated.
+	ErrContainerRunning = errors.New("devbox container is running and has not terminated")
+
+	// ErrEphemeralContainersNotSupported is returned when the cluster does not
+	// support ephemeral containers (Kubernetes < 1.23).
+	ErrEphemeralContainersNotSupported = errors.New("ephemeral containers are not supported by this cluster")
+
+	// ErrExpiryInPast is returned when a change would move the expiry into the past.
+	ErrExpiryInPast = errors.New("expiry would be in the past")
+
+	// ErrVolumeSnapshotClassNotFound is returned by CreateSnapshot when the cluster
+	// has no VolumeSnapshotClass.
+	ErrVolumeSnapshotClassNotFound = errors.New("no volume snapshot class found")
+
+	// ErrForbidden is returned when the SDK credentials lack the permissions an
+	// operation needs.
+	ErrForbidden = errors.New("forbidden")
+
+	// ErrSSHUnavailable is returned when no SSH connection to the devbox can be
+	// established.
+	ErrSSHUnavailable = errors.New("devbox ssh unavailable")
+
+	// ErrPrivilegedNotAllowed is returned when the cluster refuses to run the
+	// devbox container privileged.
+	ErrPrivilegedNotAllowed = errors.New("privileged devbox not allowed")
+
+	// ErrIPv6NotEnabled is returned by GetIPv6Address when the devbox has no IPv6
+	// address assigned.
+	ErrIPv6NotEnabled = errors.New("devbox has no ipv6 address")
+
+	// ErrNoEndpoints is returned by GetServiceEndpoints when the devbox services
+	// have no endpoints.
+	ErrNoEndpoints = errors.New("no service endpoints for devbox")
+
+	// ErrDevboxLocked is returned by destructive operations on a devbox that is
+	// locked with Lock.
+	ErrDevboxLocked = errors.New("devbox is locked")
+
+	// ErrDevboxPinned is returned by PatchImage on a devbox pinned with
+	// PinToImage.
+	ErrDevboxPinned = errors.New("devbox image is pinned")
+
+	// ErrNotFound is returned when a devbox or another object an operation
+	// needs does not exist.
+	ErrNotFound = errors.New("not found")
+
+	// ErrAlreadyExists is returned when creating a devbox whose name is
+	// taken.
+	ErrAlreadyExists = errors.New("already exists")
+
+	// ErrTimeout matches every *TimeoutError.
+	ErrTimeout = errors.New("timed out")
+
+	// ErrDevboxFailed is returned by WaitForState and WaitForPhase when the
+	// devbox enters the Error phase while waiting for another.
+	ErrDevboxFailed = errors.New("devbox entered the error phase")
+
+	// ErrReleaseFailed is returned by Release.WaitForCompletion when the
+	// release fails.
+	ErrReleaseFailed = errors.New("release failed")
+
+	// ErrNoRelease is returned by Devbox.Clone when FromLatestRelease is set and
+	// the devbox has no completed release.
+	ErrNoRelease = errors.New("devbox has no completed release")
+
+	// ErrManagedKey is returned by SSHRemoveID for the devbox's own public key,
+	// which the SDK needs to connect.
+	ErrManagedKey = errors.New("cannot remove the devbox's managed ssh key")
+)
+
+// apiError converts a Kubernetes API error into the matching error of this
+// package, keeping the original in the chain so that the apierrors helpers
+// still recognize it. Other errors are returned unchanged.
+func apiError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case apierrors.IsNotFound(err):
+		return fmt.Errorf("%w: %w", ErrNotFound, err)
+	case apierrors.IsAlreadyExists(err):
+		return fmt.Errorf("%w: %w", ErrAlreadyExists, err)
+	case apierrors.IsConflict(err):
+		return &ConflictError{name: statusName(err), err: err}
+	case apierrors.IsForbidden(err) && strings.Contains(err.Error(), "exceeded quota"):
+		return fmt.Errorf("%w: %w", ErrQuotaExceeded, err)
+	case apierrors.IsForbidden(err):
+		return fmt.Errorf("%w: %w", ErrForbidden, err)
+	case apierrors.IsTimeout(err), apierrors.IsServerTimeout(err):
+		return &TimeoutError{message: "api request", err: err}
+	}
+	return err
+}
+
+// statusName returns the object name recorded in a Kubernetes API error.
+func statusName(err error) string {
+	var status apierrors.APIStatus
+	if errors.As(err, &status) && status.Status().Details != nil {
+		return status.Status().Details.Name
+	}
+	return ""
+}
+
+// ConflictError is returned when a mutation was rejected because the devbox
+// changed since it was last read. Call RefreshInfo and retry.
+type ConflictError struct {
+	name string
+	err  error
+}
+
+func (e *ConflictError) Error() string {
+	return "devbox " + e.name + " was modified concurrently: " + e.err.Error()
+}
+
+func (e *ConflictError) Unwrap() error {
+	return e.err
+}
+
+// TimeoutError is returned when a wait or an API request times out. It
+// matches ErrTimeout with errors.Is and unwraps to the underlying cause,
+// context.DeadlineExceeded for waits.
+type TimeoutError struct {
+	message string
+	timeout time.Duration
+	err     error
+}
+
+func (e *TimeoutError) Error() string {
+	if e.timeout == 0 {
+		return e.message + " timed out: " + e.err.Error()
+	}
+	return e.message + " (timeout: " + e.timeout.String() + ")"
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.err
+}
+
+func (e *TimeoutError) Is(target error) bool {
+	return target == ErrTimeout
+}
+
+// RecreateError is returned when a devbox was deleted in order to be
+// recreated, but could not be created again. The devbox no longer exists;
+// if its volumes were snapshotted first, Snapshots names the volume snapshots
+// its data can be restored from.
+type RecreateError struct {
+	Name      string
+	Snapshots []string
+	Err       error
+}
+
+func (e *RecreateError) Error() string {
+	msg := "devbox " + e.Name + " was deleted but not recreated"
+	if len(e.Snapshots) > 0 {
+		msg += " (data kept in volume snapshots " + strings.Join(e.Snapshots, ", ") + ")"
+	}
+	return msg + ": " + e.Err.Error()
+}
+
+func (e *RecreateError) Unwrap() error {
+	return e.Err
+}
+
+// QuotaExceededError reports by how much a request exceeds the namespace quota.
+// It matches ErrQuotaExceeded with errors.Is.
+type QuotaExceededError struct {
+	Resource  string
+	Shortfall float64
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("namespace quota exceeded: %s short by %g", e.Resource, e.Shortfall)
+}
+
+func (e *QuotaExceededError) Is(target error) bool {
+	return target == ErrQuotaExceeded
+}
+
+// RequestExceedsLimitError is returned when a resource request is larger than
+// its limit.
+type RequestExceedsLimitError struct {
+	Resource string
+}
+
+func (e *RequestExceedsLimitError) Error() string {
+	return e.Resource + " request exceeds its limit"
+}
+
+// LimitExceedsQuotaError is returned when a resource limit does not fit in the
+// namespace quota. It matches ErrQuotaExceeded with errors.Is.
+type LimitExceedsQuotaError struct {
+	Resource string
+}
+
+func (e *LimitExceedsQuotaError) Error() string {
+	return e.Resource + " limit exceeds the namespace quota"
+}
+
+func (e *LimitExceedsQuotaError) Is(target error) bool {
+	return target == ErrQuotaExceeded
+}
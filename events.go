@@ -0,0 +1,171 @@
+package devbox
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+// DevboxEvent is a Kubernetes event concerning the devbox.
+type DevboxEvent struct {
+	Type      string // Normal or Warning
+	Reason    string
+	Message   string
+	Kind      string // kind of the object the event is about
+	Object    string // name of the object the event is about
+	Source    string
+	Count     int32
+	FirstSeen time.Time
+	LastSeen  time.Time
+	// Err is set only on the last event WatchEvents sends when the watch
+	// failed for good; its other fields are then empty.
+	Err error
+}
+
+// ListEvents returns the Kubernetes events whose involved object is named
+// after the devbox, oldest first.
+func (d *Devbox) ListEvents(ctx context.Context) ([]DevboxEvent, error) {
+	events, err := d.listEvents(ctx, d.eventSelector())
+	if err != nil {
+		return nil, err
+	}
+	sortEvents(events)
+	return events, nil
+}
+
+// Events returns the events of ListEvents together with those of the pods of
+// the devbox, oldest first. Pod events explain most devboxes stuck in
+// Pending, such as image pull errors, scheduling failures and OOM kills.
+func (d *Devbox) Events(ctx context.Context) ([]DevboxEvent, error) {
+	events, err := d.listEvents(ctx, d.eventSelector())
+	if err != nil {
+		return nil, err
+	}
+	pods, err := d.sdk.client.Clientset().CoreV1().Pods(d.crd.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{labelAppName: d.crd.Name}).String(),
+	})
+	if err != nil {
+		return nil, apiError(err)
+	}
+	for _, pod := range pods.Items {
+		if pod.Name == d.crd.Name {
+			// Already matched by the devbox selector.
+			continue
+		}
+		podEvents, err := d.listEvents(ctx, fields.SelectorFromSet(fields.Set{
+			"involvedObject.kind": "Pod",
+			"involvedObject.name": pod.Name,
+		}).String())
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, podEvents...)
+	}
+	sortEvents(events)
+	return events, nil
+}
+
+func (d *Devbox) listEvents(ctx context.Context, selector string) ([]DevboxEvent, error) {
+	list, err := d.sdk.client.Clientset().CoreV1().Events(d.crd.Namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: selector,
+	})
+	if err != nil {
+		return nil, apiError(err)
+	}
+	events := make([]DevboxEvent, len(list.Items))
+	for i := range list.Items {
+		events[i] = devboxEvent(&list.Items[i])
+	}
+	return events, nil
+}
+
+func sortEvents(events []DevboxEvent) {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].LastSeen.Before(events[j].LastSeen)
+	})
+}
+
+// WatchEvents streams the events ListEvents would return as they are created
+// or updated. Past events are not replayed. The watch is resumed after
+// server-side timeouts and transient failures, and the channel is closed
+// when ctx is cancelled or after an event with Err set.
+func (d *Devbox) WatchEvents(ctx context.Context) (<-chan DevboxEvent, error) {
+	events := d.sdk.client.Clientset().CoreV1().Events(d.crd.Namespace)
+	selector := d.eventSelector()
+
+	raw, err := watchResumable(ctx, &cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			opts.FieldSelector = selector
+			return events.List(ctx, opts)
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			opts.FieldSelector = selector
+			return events.Watch(ctx, opts)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan DevboxEvent)
+	go func() {
+		defer close(out)
+		for e := range raw {
+			if e.Type == watch.Error {
+				select {
+				case out <- DevboxEvent{Err: apiError(apierrors.FromObject(e.Object))}:
+				case <-ctx.Done():
+				}
+				return
+			}
+			event, ok := e.Object.(*corev1.Event)
+			if !ok || e.Type == watch.Deleted {
+				continue
+			}
+			select {
+			case out <- devboxEvent(event):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+func (d *Devbox) eventSelector() string {
+	return fields.OneTermEqualSelector("involvedObject.name", d.crd.Name).String()
+}
+
+func devboxEvent(e *corev1.Event) DevboxEvent {
+	event := DevboxEvent{
+		Type:      e.Type,
+		Reason:    e.Reason,
+		Message:   e.Message,
+		Kind:      e.InvolvedObject.Kind,
+		Object:    e.InvolvedObject.Name,
+		Source:    e.Source.Component,
+		Count:     e.Count,
+		FirstSeen: e.FirstTimestamp.Time,
+		LastSeen:  e.LastTimestamp.Time,
+	}
+	// Events created through the events.k8s.io API only set EventTime.
+	if event.LastSeen.IsZero() {
+		event.LastSeen = e.EventTime.Time
+	}
+	if event.FirstSeen.IsZero() {
+		event.FirstSeen = event.LastSeen
+	}
+	if event.Source == "" {
+		event.Source = e.ReportingController
+	}
+	return event
+}
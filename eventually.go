@@ -0,0 +1,34 @@
+package devbox
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gitlayzer/devbox-sdk-go/types"
+)
+
+// EventuallyConsistent polls the named devbox until check returns true for it
+// and returns the devbox that passed. Each poll reads the devbox afresh and
+// passes check a new *Devbox, so check always sees current state; polling
+// backs off as in WaitForReady. A devbox that does not exist yet, for
+// instance because it was only just created, is polled until it appears.
+//
+// check may be called many times and must therefore be free of side effects.
+func (s *DevboxSDK) EventuallyConsistent(ctx context.Context, name string, check func(*Devbox) bool, opts types.WaitForReadyOptions) (*Devbox, error) {
+	var found *Devbox
+	err := s.pollWithBackoff(ctx, opts, "waiting for devbox condition", func() (bool, error) {
+		crd, err := s.getDevbox(ctx, name)
+		if errors.Is(err, ErrNotFound) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if d := newDevbox(crd, s); check(d) {
+			found = d
+			return true, nil
+		}
+		return false, nil
+	})
+	return found, err
+}
@@ -0,0 +1,181 @@
+package devbox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// ExecOptions contains options for running commands in a devbox.
+type ExecOptions struct {
+	// ConcurrencyLimit caps how many commands ExecParallel runs at once.
+	// Zero means no limit.
+	ConcurrencyLimit int
+
+	// The following apply to Exec only.
+
+	// Stdin is fed to the command if non-nil.
+	Stdin io.Reader
+	// Env sets environment variables for the command.
+	Env map[string]string
+	// WorkingDir is the directory the command runs in. Empty means the
+	// login directory.
+	WorkingDir string
+	// Timeout bounds the command's run time. Zero means no timeout.
+	Timeout time.Duration
+}
+
+// ExecResult is the outcome of a command run in a devbox. A non-zero exit code
+// is not an error; Err is set only if the command could not be run.
+type ExecResult struct {
+	Command  string
+	ExitCode int
+	Stdout   string
+	Stderr   string
+	Err      error
+}
+
+// Exec runs cmd in the devbox through a shell over SSH, using the devbox key
+// pair and network configuration. The returned error is non-nil only if the
+// command could not be run or did not finish; it is also stored in the
+// result's Err.
+func (d *Devbox) Exec(ctx context.Context, cmd string, opts ExecOptions) (_ *ExecResult, err error) {
+	ctx, span := d.startSpan(ctx, "devbox.Exec")
+	defer func() { endSpan(span, err) }()
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	client, err := d.SSHDial(ctx)
+	if err != nil {
+		err = fmt.Errorf("%w: %w", ErrSSHUnavailable, err)
+		return &ExecResult{Command: cmd, ExitCode: -1, Err: err}, err
+	}
+	defer client.Close()
+
+	result := execSession(ctx, client, execCommand(cmd, opts), opts.Stdin)
+	result.Command = cmd
+	return &result, result.Err
+}
+
+// execCommand prefixes cmd with the working directory and environment of
+// opts. Variables are set in the command line rather than through the SSH
+// protocol, which most servers restrict.
+func execCommand(cmd string, opts ExecOptions) string {
+	var b strings.Builder
+	if opts.WorkingDir != "" {
+		b.WriteString("cd " + shellQuote(opts.WorkingDir) + " && ")
+	}
+	if len(opts.Env) > 0 {
+		names := make([]string, 0, len(opts.Env))
+		for name := range opts.Env {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		b.WriteString("env")
+		for _, name := range names {
+			b.WriteString(" " + shellQuote(name+"="+opts.Env[name]))
+		}
+		b.WriteString(" ")
+	}
+	if b.Len() == 0 {
+		return cmd
+	}
+	return b.String() + "sh -c " + shellQuote(cmd)
+}
+
+// ExecParallel runs commands concurrently, each in its own SSH session over a
+// shared connection, and returns their results in input order. If the
+// connection fails, every result has an error matching ErrSSHUnavailable.
+func (d *Devbox) ExecParallel(ctx context.Context, commands []string, opts ExecOptions) []ExecResult {
+	results := make([]ExecResult, len(commands))
+
+	client, err := d.SSHDial(ctx)
+	if err != nil {
+		err = fmt.Errorf("%w: %w", ErrSSHUnavailable, err)
+		for i, cmd := range commands {
+			results[i] = ExecResult{Command: cmd, ExitCode: -1, Err: err}
+		}
+		return results
+	}
+	defer client.Close()
+
+	limit := opts.ConcurrencyLimit
+	if limit <= 0 {
+		limit = len(commands)
+	}
+	sem := make(chan struct{}, limit)
+
+	var wg sync.WaitGroup
+	for i, cmd := range commands {
+		wg.Add(1)
+		go func(i int, cmd string) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				results[i] = ExecResult{Command: cmd, ExitCode: -1, Err: ctx.Err()}
+				return
+			}
+			results[i] = execSession(ctx, client, cmd, nil)
+		}(i, cmd)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// execSession runs cmd in a new session on client, feeding it stdin if
+// non-nil.
+func execSession(ctx context.Context, client *ssh.Client, cmd string, stdin io.Reader) ExecResult {
+	result := ExecResult{Command: cmd, ExitCode: -1}
+
+	session, err := client.NewSession()
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	defer session.Close()
+
+	var stdout, stderr bytes.Buffer
+	session.Stdin = stdin
+	session.Stdout = &stdout
+	session.Stderr = &stderr
+
+	done := make(chan error, 1)
+	go func() { done <- session.Run(cmd) }()
+
+	select {
+	case <-ctx.Done():
+		session.Close()
+		result.Err = ctx.Err()
+		return result
+	case err = <-done:
+	}
+
+	result.Stdout = stdout.String()
+	result.Stderr = stderr.String()
+
+	var exitErr *ssh.ExitError
+	switch {
+	case err == nil:
+		result.ExitCode = 0
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitStatus()
+	default:
+		result.Err = err
+	}
+	return result
+}
@@ -0,0 +1,60 @@
+package devbox
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const annotationExpiresAt = "devbox.sealos.run/expires-at"
+
+// ExpiresAt returns the expiry time of the devbox, or nil if none is set.
+func (d *Devbox) ExpiresAt() *time.Time {
+	raw, ok := d.crd.Annotations[annotationExpiresAt]
+	if !ok {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// ExtendExpiry moves the expiry of the devbox later by the given duration. If
+// no expiry is set, it is set to now plus by.
+func (d *Devbox) ExtendExpiry(ctx context.Context, by time.Duration) error {
+	if err := d.RefreshInfo(ctx); err != nil {
+		return err
+	}
+
+	expiry := time.Now().Add(by)
+	if current := d.ExpiresAt(); current != nil {
+		expiry = current.Add(by)
+	}
+	return d.setExpiry(ctx, expiry)
+}
+
+// ShortenExpiry moves the expiry of the devbox earlier by the given duration.
+// It returns ErrExpiryInPast rather than setting an expiry that has passed.
+func (d *Devbox) ShortenExpiry(ctx context.Context, by time.Duration) error {
+	if err := d.RefreshInfo(ctx); err != nil {
+		return err
+	}
+
+	current := d.ExpiresAt()
+	if current == nil {
+		return errors.New("devbox has no expiry set")
+	}
+	expiry := current.Add(-by)
+	if expiry.Before(time.Now()) {
+		return ErrExpiryInPast
+	}
+	return d.setExpiry(ctx, expiry)
+}
+
+func (d *Devbox) setExpiry(ctx context.Context, expiry time.Time) error {
+	return d.patchAnnotations(ctx, map[string]interface{}{
+		annotationExpiresAt: expiry.UTC().Format(time.RFC3339),
+	})
+}
@@ -0,0 +1,41 @@
+package devbox
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// GetFileChecksum returns the hex-encoded SHA-256 checksum of a file inside
+// the devbox.
+func (d *Devbox) GetFileChecksum(ctx context.Context, remotePath string) (string, error) {
+	out, err := d.runSSH(ctx, "sha256sum -- "+shellQuote(remotePath), nil)
+	if err != nil {
+		return "", err
+	}
+	return parseSHA256Sum(string(out))
+}
+
+// VerifyFile reports whether the file inside the devbox has the expected
+// SHA-256 checksum.
+func (d *Devbox) VerifyFile(ctx context.Context, remotePath, expectedChecksum string) (bool, error) {
+	sum, err := d.GetFileChecksum(ctx, remotePath)
+	if err != nil {
+		return false, err
+	}
+	return strings.EqualFold(sum, strings.TrimSpace(expectedChecksum)), nil
+}
+
+// parseSHA256Sum extracts the checksum from sha256sum output.
+func parseSHA256Sum(out string) (string, error) {
+	fields := strings.Fields(out)
+	if len(fields) == 0 {
+		return "", fmt.Errorf("unexpected sha256sum output: %q", out)
+	}
+	sum := strings.TrimPrefix(fields[0], `\`)
+	if b, err := hex.DecodeString(sum); err != nil || len(b) != 32 {
+		return "", fmt.Errorf("unexpected sha256sum output: %q", out)
+	}
+	return sum, nil
+}
@@ -0,0 +1,116 @@
+package devbox
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+const emptySHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+func TestParseSHA256Sum(t *testing.T) {
+	const sum = emptySHA256
+	tests := []struct {
+		name    string
+		out     string
+		want    string
+		wantErr bool
+	}{
+		{name: "plain", out: sum + "  /home/devbox/empty\n", want: sum},
+		{name: "binary mode", out: sum + " */home/devbox/empty\n", want: sum},
+		{name: "escaped file name", out: `\` + sum + `  /home/devbox/a\nb` + "\n", want: sum},
+		{name: "empty", out: "", wantErr: true},
+		{name: "error message", out: "sha256sum: /nope: No such file or directory\n", wantErr: true},
+		{name: "too short", out: sum[:62] + "  /home/devbox/empty\n", wantErr: true},
+		{name: "not hex", out: "z" + sum[1:] + "  /home/devbox/empty\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSHA256Sum(tt.out)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSHA256Sum() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseSHA256Sum() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// sha256sumHandler fakes sha256sum for a devbox holding only empty files,
+// whose paths are listed in files.
+func sha256sumHandler(files ...string) sshHandler {
+	return func(cmd string, stdout, stderr io.Writer) uint32 {
+		for _, path := range files {
+			if cmd == "sha256sum -- "+shellQuote(path) {
+				io.WriteString(stdout, emptySHA256+"  "+path+"\n")
+				return 0
+			}
+		}
+		io.WriteString(stderr, "sha256sum: No such file or directory\n")
+		return 1
+	}
+}
+
+func TestGetFileChecksum(t *testing.T) {
+	ctx := context.Background()
+	d := newDevbox(fakeDevbox("alice"), &DevboxSDK{})
+	const path = "/home/devbox/it's here.txt"
+	commands := fakeSSH(t, sha256sumHandler(path))
+
+	sum, err := d.GetFileChecksum(ctx, path)
+	if err != nil {
+		t.Fatalf("GetFileChecksum: %v", err)
+	}
+	if sum != emptySHA256 {
+		t.Errorf("GetFileChecksum = %q, want %q", sum, emptySHA256)
+	}
+	if got, want := commands(), `sha256sum -- '/home/devbox/it'\''s here.txt'`; len(got) != 1 || got[0] != want {
+		t.Errorf("commands = %q, want [%q]", got, want)
+	}
+
+	_, err = d.GetFileChecksum(ctx, "/home/devbox/missing")
+	if err == nil || !strings.Contains(err.Error(), "No such file or directory") {
+		t.Errorf("GetFileChecksum of a missing file: error = %v, want the sha256sum error", err)
+	}
+	if errors.Is(err, ErrSSHUnavailable) {
+		t.Errorf("GetFileChecksum of a missing file: error = %v, should not match ErrSSHUnavailable", err)
+	}
+
+	unavailableSSH(t)
+	if _, err := d.GetFileChecksum(ctx, path); !errors.Is(err, ErrSSHUnavailable) {
+		t.Errorf("GetFileChecksum without SSH: error = %v, want ErrSSHUnavailable", err)
+	}
+}
+
+func TestVerifyFile(t *testing.T) {
+	ctx := context.Background()
+	d := newDevbox(fakeDevbox("alice"), &DevboxSDK{})
+	const path = "/home/devbox/empty"
+	fakeSSH(t, sha256sumHandler(path))
+
+	for expected, want := range map[string]bool{
+		emptySHA256:                         true,
+		strings.ToUpper(emptySHA256) + "\n": true,
+		strings.Repeat("0", 64):             false,
+	} {
+		ok, err := d.VerifyFile(ctx, path, expected)
+		if err != nil {
+			t.Fatalf("VerifyFile(%q): %v", expected, err)
+		}
+		if ok != want {
+			t.Errorf("VerifyFile(%q) = %v, want %v", expected, ok, want)
+		}
+	}
+
+	if ok, err := d.VerifyFile(ctx, "/home/devbox/missing", emptySHA256); err == nil || ok {
+		t.Errorf("VerifyFile of a missing file = %v, %v, want an error", ok, err)
+	}
+
+	unavailableSSH(t)
+	if _, err := d.VerifyFile(ctx, path, emptySHA256); !errors.Is(err, ErrSSHUnavailable) {
+		t.Errorf("VerifyFile without SSH: error = %v, want ErrSSHUnavailable", err)
+	}
+}
@@ -0,0 +1,80 @@
+package devbox
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/gitlayzer/devbox-sdk-go/api/v1alpha2"
+)
+
+// FindDevboxByNode returns the devboxes scheduled on nodeName. It uses a
+// status.node field selector where the CRD supports it and falls back to
+// filtering the full list otherwise.
+func (s *DevboxSDK) FindDevboxByNode(ctx context.Context, nodeName string) ([]*Devbox, error) {
+	list, err := s.client.List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("status.node", nodeName).String(),
+	})
+	if err == nil {
+		return s.wrapList(list, nil), nil
+	}
+	if !apierrors.IsBadRequest(err) {
+		return nil, err
+	}
+
+	list, err = s.client.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return s.wrapList(list, func(d *v1alpha2.Devbox) bool {
+		return d.Status.Node == nodeName
+	}), nil
+}
+
+// FindDevboxByLabel returns the devboxes labeled key=value. The selector is
+// evaluated by the API server, so only matching devboxes are transferred.
+func (s *DevboxSDK) FindDevboxByLabel(ctx context.Context, key, value string) ([]*Devbox, error) {
+	selector, err := labels.ValidatedSelectorFromSet(labels.Set{key: value})
+	if err != nil {
+		return nil, err
+	}
+	list, err := s.client.List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	return s.wrapList(list, nil), nil
+}
+
+// FindDevboxByAnnotation returns the devboxes annotated with key=value.
+// Kubernetes cannot select custom resources by annotation, so every devbox in
+// the namespace is listed and filtered client-side. This costs time and
+// memory proportional to the namespace size rather than the number of
+// matches; prefer FindDevboxByLabel for lookups on large namespaces.
+func (s *DevboxSDK) FindDevboxByAnnotation(ctx context.Context, key, value string) ([]*Devbox, error) {
+	list, err := s.client.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return s.wrapList(list, func(d *v1alpha2.Devbox) bool {
+		v, ok := d.Annotations[key]
+		return ok && v == value
+	}), nil
+}
+
+// wrapList wraps the items of list that pass keep (all if keep is nil) and
+// refreshes their cache entries.
+func (s *DevboxSDK) wrapList(list *v1alpha2.DevboxList, keep func(*v1alpha2.Devbox) bool) []*Devbox {
+	var devboxes []*Devbox
+	for i := range list.Items {
+		crd := &list.Items[i]
+		if keep != nil && !keep(crd) {
+			continue
+		}
+		s.cache.Set(crd.Name, crd)
+		devboxes = append(devboxes, newDevbox(crd, s))
+	}
+	return devboxes
+}
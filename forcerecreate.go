@@ -0,0 +1,119 @@
+package devbox
+
+import (
+	"context"
+	"errors"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	"github.com/gitlayzer/devbox-sdk-go/types"
+)
+
+// ForceRecreate deletes the devbox and creates it again from the same spec,
+// for when a devbox is wedged and restarting it does not help. The new
+// devbox has a new UID and no runtime status.
+//
+// If preserveData is true, every volume is snapshotted first, and volumes
+// that are deleted together with the devbox are recreated from their
+// snapshots under the same name before the new devbox is created. The
+// snapshots are kept afterwards.
+//
+// It returns ErrDevboxLocked if the devbox is locked. If the devbox was
+// deleted but could not be created again, the error is a *RecreateError
+// whose Snapshots name the snapshots holding its data.
+func (d *Devbox) ForceRecreate(ctx context.Context, preserveData bool) (*Devbox, error) {
+	if err := d.RefreshInfo(ctx); err != nil {
+		return nil, err
+	}
+	if d.IsLocked() {
+		return nil, ErrDevboxLocked
+	}
+	if !preserveData {
+		return d.recreate(ctx, nil)
+	}
+
+	snapshots, pvcs, err := d.snapshotVolumes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.waitSnapshotsReady(ctx, snapshots); err != nil {
+		return nil, err
+	}
+
+	oldUID := d.crd.UID
+	recreated, err := d.recreate(ctx, func() error {
+		for i := range pvcs {
+			if err := d.restoreVolume(ctx, oldUID, &pvcs[i], snapshots[i].Name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	var recreateErr *RecreateError
+	if errors.As(err, &recreateErr) {
+		for _, s := range snapshots {
+			recreateErr.Snapshots = append(recreateErr.Snapshots, s.Name)
+		}
+	}
+	return recreated, err
+}
+
+// waitSnapshotsReady polls until all snapshots are ready to use.
+func (d *Devbox) waitSnapshotsReady(ctx context.Context, snapshots []VolumeSnapshot) error {
+	client, err := d.snapshotClient()
+	if err != nil {
+		return err
+	}
+	return d.sdk.pollWithBackoff(ctx, types.WaitForReadyOptions{}, "waiting for volume snapshots to be ready", func() (bool, error) {
+		for _, s := range snapshots {
+			snapshot, err := client.SnapshotV1().VolumeSnapshots(d.crd.Namespace).Get(ctx, s.Name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			if !volumeSnapshot(snapshot).ReadyToUse {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+}
+
+// restoreVolume recreates source from the snapshot once it has been
+// garbage-collected with the devbox whose UID is ownerUID, waiting for at most
+// the default wait timeout. A PVC that survives the devbox, because it is not
+// owned by it, is left alone.
+func (d *Devbox) restoreVolume(ctx context.Context, ownerUID k8stypes.UID, source *corev1.PersistentVolumeClaim, snapshotName string) error {
+	pvcs := d.sdk.client.Clientset().CoreV1().PersistentVolumeClaims(d.crd.Namespace)
+	snapshots, err := d.snapshotClient()
+	if err != nil {
+		return err
+	}
+	snapshot, err := snapshots.SnapshotV1().VolumeSnapshots(d.crd.Namespace).Get(ctx, snapshotName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	return d.sdk.pollWithBackoff(ctx, types.WaitForReadyOptions{}, "waiting for volume "+source.Name+" to be released", func() (bool, error) {
+		pvc, err := pvcs.Get(ctx, source.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			_, err = pvcs.Create(ctx, d.restoredPVC(source.Name, source, snapshot), metav1.CreateOptions{})
+			return err == nil, err
+		}
+		if err != nil {
+			return false, err
+		}
+		return pvc.DeletionTimestamp == nil && !ownedBy(pvc.OwnerReferences, ownerUID), nil
+	})
+}
+
+func ownedBy(refs []metav1.OwnerReference, uid k8stypes.UID) bool {
+	for _, ref := range refs {
+		if ref.UID == uid {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,27 @@
+package devbox
+
+import "context"
+
+const annotationResourcesFrozen = "devbox.sealos.run/resources-frozen"
+
+// FreezeResources marks the resources of the devbox as frozen. While frozen,
+// the operator's admission webhook rejects resource changes such as those made
+// by UpdateResources and SetResourceRequirements.
+func (d *Devbox) FreezeResources(ctx context.Context) error {
+	return d.patchAnnotations(ctx, map[string]interface{}{
+		annotationResourcesFrozen: "true",
+	})
+}
+
+// ThawResources lifts a freeze set by FreezeResources.
+func (d *Devbox) ThawResources(ctx context.Context) error {
+	return d.patchAnnotations(ctx, map[string]interface{}{
+		annotationResourcesFrozen: nil,
+	})
+}
+
+// AreResourcesFrozen reports whether the resources of the devbox are frozen,
+// as of the last refresh.
+func (d *Devbox) AreResourcesFrozen() bool {
+	return d.crd.Annotations[annotationResourcesFrozen] == "true"
+}
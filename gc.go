@@ -0,0 +1,131 @@
+package devbox
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	"github.com/gitlayzer/devbox-sdk-go/api/v1alpha2"
+)
+
+const (
+	annotationLastSDKAccess = "devbox.sealos.run/last-sdk-access"
+
+	// lastAccessGranularity is how stale the last-access annotation may get
+	// before the SDK rewrites it, keeping the annotation from churning.
+	lastAccessGranularity = time.Hour
+)
+
+// GCResult lists what GarbageCollect did. In a dry run, the devboxes that
+// would have been deleted are listed in DryRun instead of Deleted.
+type GCResult struct {
+	Deleted []string
+	DryRun  []string
+	Errors  []BatchResult
+}
+
+// GarbageCollect deletes the stopped or shut down devboxes in the SDK namespace
+// that no SDK has accessed for longer than olderThan. Access is recorded by
+// every SDK operation on a devbox, whether it changes the devbox, reads it
+// with RefreshInfo or connects to it over SSH as Exec, Shell and SFTP do, and
+// by RecordAccess, with a granularity of one hour; devboxes never accessed
+// count from their creation. Locked and pinned
+// devboxes are never collected. A failure to delete one devbox is recorded in
+// Errors and does not stop the others.
+func (s *DevboxSDK) GarbageCollect(ctx context.Context, olderThan time.Duration, dryRun bool) (GCResult, error) {
+	var result GCResult
+
+	list, err := s.client.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return result, err
+	}
+
+	cutoff := time.Now().Add(-olderThan)
+	for _, d := range s.wrapList(list, nil) {
+		switch d.crd.Status.Phase {
+		case v1alpha2.DevboxPhaseStopped, v1alpha2.DevboxPhaseShutdown:
+		default:
+			continue
+		}
+		if d.IsLocked() || d.IsPinned() || !d.lastAccessed().Before(cutoff) {
+			continue
+		}
+
+		if dryRun {
+			result.DryRun = append(result.DryRun, d.Name())
+			continue
+		}
+		if err := d.Delete(ctx); err != nil {
+			result.Errors = append(result.Errors, BatchResult{Name: d.Name(), Err: err})
+			continue
+		}
+		result.Deleted = append(result.Deleted, d.Name())
+	}
+	return result, nil
+}
+
+// lastAccessed returns when an SDK last accessed the devbox, falling back to
+// its creation time.
+func (d *Devbox) lastAccessed() time.Time {
+	if raw, ok := d.crd.Annotations[annotationLastSDKAccess]; ok {
+		if t, err := time.Parse(time.RFC3339, raw); err == nil {
+			return t
+		}
+	}
+	return d.crd.CreationTimestamp.Time
+}
+
+// accessStale reports whether the last-access annotation should be rewritten.
+func (d *Devbox) accessStale() bool {
+	raw, ok := d.crd.Annotations[annotationLastSDKAccess]
+	if !ok {
+		return true
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	return err != nil || time.Since(t) > lastAccessGranularity
+}
+
+// RecordAccess records a use of the devbox for GarbageCollect. SDK
+// operations record their access themselves; RecordAccess is for callers
+// that use a devbox by other means, e.g. with an SSH client of their own.
+// Like the record kept by SDK operations, it is only rewritten once it is an
+// hour old. The patch is unconditional, so it cannot conflict with
+// concurrent changes.
+func (d *Devbox) RecordAccess(ctx context.Context) error {
+	if !d.accessStale() {
+		return nil
+	}
+	data, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				annotationLastSDKAccess: time.Now().UTC().Format(time.RFC3339),
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	updated, err := d.sdk.client.Patch(ctx, d.crd.Name, k8stypes.MergePatchType, data)
+	if err != nil {
+		return apiError(err)
+	}
+	d.crd = updated
+	d.sdk.cache.Set(d.crd.Name, updated)
+	return nil
+}
+
+// noteAccess records an access by an SDK operation that does not change the
+// devbox, as RecordAccess does. Access tracking is best effort, so a failure
+// is logged rather than failing the operation.
+func (d *Devbox) noteAccess(ctx context.Context) {
+	if err := d.RecordAccess(ctx); err != nil {
+		d.sdk.logAttrs(ctx, slog.LevelWarn, "recording devbox access failed",
+			slog.String("namespace", d.crd.Namespace),
+			slog.String("devbox", d.crd.Name),
+			errorAttr(err))
+	}
+}
@@ -0,0 +1,141 @@
+package devbox
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// HealthCheckType selects how a health check probes the devbox.
+type HealthCheckType string
+
+const (
+	HealthCheckHTTP HealthCheckType = "HTTP"
+	HealthCheckTCP  HealthCheckType = "TCP"
+	HealthCheckExec HealthCheckType = "Exec"
+)
+
+// HealthCheckSpec describes the liveness probe of the devbox container. Path
+// applies to HTTP checks, Port to HTTP and TCP checks, and Command to Exec
+// checks. Zero timing fields use the Kubernetes defaults.
+type HealthCheckSpec struct {
+	Type                HealthCheckType
+	Path                string
+	Port                int32
+	Command             []string
+	InitialDelaySeconds int32
+	PeriodSeconds       int32
+	FailureThreshold    int32
+}
+
+// ProbeResult is the observed state of the devbox liveness probe. LastFailure
+// and Message describe the most recent failure still recorded in events, if
+// any.
+type ProbeResult struct {
+	Healthy      bool
+	RestartCount int32
+	LastFailure  *time.Time
+	Message      string
+}
+
+// SetHealthCheck sets the liveness probe of the devbox container. The kubelet
+// restarts the container when the probe keeps failing. It takes effect when
+// the pod is next created.
+func (d *Devbox) SetHealthCheck(ctx context.Context, check HealthCheckSpec) error {
+	probe := &corev1.Probe{
+		InitialDelaySeconds: check.InitialDelaySeconds,
+		PeriodSeconds:       check.PeriodSeconds,
+		FailureThreshold:    check.FailureThreshold,
+	}
+	switch check.Type {
+	case HealthCheckHTTP:
+		probe.HTTPGet = &corev1.HTTPGetAction{Path: check.Path, Port: intstr.FromInt(int(check.Port))}
+	case HealthCheckTCP:
+		probe.TCPSocket = &corev1.TCPSocketAction{Port: intstr.FromInt(int(check.Port))}
+	case HealthCheckExec:
+		if len(check.Command) == 0 {
+			return fmt.Errorf("exec health check needs a command")
+		}
+		probe.Exec = &corev1.ExecAction{Command: check.Command}
+	default:
+		return fmt.Errorf("unsupported health check type %q", check.Type)
+	}
+
+	patch, err := runtime.DefaultUnstructuredConverter.ToUnstructured(probe)
+	if err != nil {
+		return err
+	}
+	// Replace rather than merge, in a single patch so that the devbox never
+	// goes without a probe: null every field the new probe leaves unset,
+	// including the handler of another type.
+	for _, field := range probeFields {
+		if _, ok := patch[field]; !ok {
+			patch[field] = nil
+		}
+	}
+	return d.patchConfig(ctx, map[string]interface{}{"livenessProbe": patch})
+}
+
+// probeFields are the JSON fields of a corev1.Probe.
+var probeFields = []string{
+	"exec",
+	"httpGet",
+	"tcpSocket",
+	"grpc",
+	"initialDelaySeconds",
+	"timeoutSeconds",
+	"periodSeconds",
+	"successThreshold",
+	"failureThreshold",
+	"terminationGracePeriodSeconds",
+}
+
+// RemoveHealthCheck removes the liveness probe of the devbox container.
+func (d *Devbox) RemoveHealthCheck(ctx context.Context) error {
+	return d.patchConfig(ctx, map[string]interface{}{"livenessProbe": nil})
+}
+
+// GetHealthCheckStatus returns the state of the liveness probe of the devbox
+// container, from its pod status and recent Unhealthy events.
+func (d *Devbox) GetHealthCheckStatus(ctx context.Context) (*ProbeResult, error) {
+	pod, err := d.getPod(ctx)
+	if err != nil {
+		return nil, err
+	}
+	status, err := d.mainContainerStatus(pod)
+	if err != nil {
+		return nil, err
+	}
+	result := &ProbeResult{
+		Healthy:      status.Ready,
+		RestartCount: status.RestartCount,
+	}
+
+	selector := fields.Set{
+		"involvedObject.name": pod.Name,
+		"reason":              "Unhealthy",
+	}.AsSelector().String()
+	events, err := d.sdk.client.Clientset().CoreV1().Events(pod.Namespace).List(ctx, metav1.ListOptions{FieldSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+	for i := range events.Items {
+		event := devboxEvent(&events.Items[i])
+		if !strings.HasPrefix(event.Message, "Liveness probe") {
+			continue
+		}
+		if result.LastFailure == nil || event.LastSeen.After(*result.LastFailure) {
+			lastSeen := event.LastSeen
+			result.LastFailure = &lastSeen
+			result.Message = event.Message
+		}
+	}
+	return result, nil
+}
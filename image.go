@@ -0,0 +1,82 @@
+package devbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+// ImageLayer is a single step of an image's build history. Empty steps, such
+// as ENV or LABEL instructions, produce no layer and have no digest or size.
+type ImageLayer struct {
+	Digest    string
+	Command   string
+	CreatedAt time.Time
+	SizeBytes int64
+	Empty     bool
+}
+
+// GetImageHistory returns the build history of the devbox image, oldest step
+// first. Only the manifest and config are fetched from the registry.
+func (d *Devbox) GetImageHistory(ctx context.Context) ([]ImageLayer, error) {
+	img, err := remoteImage(ctx, d.Image())
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := img.Manifest()
+	if err != nil {
+		return nil, err
+	}
+	configFile, err := img.ConfigFile()
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]ImageLayer, len(configFile.History))
+	next := 0
+	for i, h := range configFile.History {
+		history[i] = ImageLayer{
+			Command:   h.CreatedBy,
+			CreatedAt: h.Created.Time,
+			Empty:     h.EmptyLayer,
+		}
+		// Non-empty history entries correspond to the manifest layers in order.
+		if !h.EmptyLayer && next < len(manifest.Layers) {
+			history[i].Digest = manifest.Layers[next].Digest.String()
+			history[i].SizeBytes = manifest.Layers[next].Size
+			next++
+		}
+	}
+	return history, nil
+}
+
+// GetImageLabels returns the labels of the devbox image. Only the image config
+// is fetched from the registry.
+func (d *Devbox) GetImageLabels(ctx context.Context) (map[string]string, error) {
+	img, err := remoteImage(ctx, d.Image())
+	if err != nil {
+		return nil, err
+	}
+	configFile, err := img.ConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	return configFile.Config.Labels, nil
+}
+
+// remoteImage returns a lazily fetched handle to the image in its registry,
+// authenticating with the default keychain.
+func remoteImage(ctx context.Context, image string) (v1.Image, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return nil, err
+	}
+	return remote.Image(ref,
+		remote.WithContext(ctx),
+		remote.WithAuthFromKeychain(authn.DefaultKeychain),
+	)
+}
@@ -0,0 +1,86 @@
+package devbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// InitContainerStatus describes an init container of the devbox pod. Phase is
+// one of Waiting, Running or Terminated.
+type InitContainerStatus struct {
+	Name       string
+	Image      string
+	Phase      string
+	ExitCode   int32
+	Reason     string
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+// ListInitContainers returns the status of the init containers of the devbox
+// pod in the order they run.
+func (d *Devbox) ListInitContainers(ctx context.Context) ([]InitContainerStatus, error) {
+	pod, err := d.getPod(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make(map[string]corev1.ContainerStatus, len(pod.Status.InitContainerStatuses))
+	for _, s := range pod.Status.InitContainerStatuses {
+		statuses[s.Name] = s
+	}
+
+	result := make([]InitContainerStatus, len(pod.Spec.InitContainers))
+	for i, c := range pod.Spec.InitContainers {
+		result[i] = InitContainerStatus{Name: c.Name, Image: c.Image, Phase: "Waiting"}
+		s, ok := statuses[c.Name]
+		if !ok {
+			continue
+		}
+		switch {
+		case s.State.Terminated != nil:
+			t := s.State.Terminated
+			result[i].Phase = "Terminated"
+			result[i].ExitCode = t.ExitCode
+			result[i].Reason = t.Reason
+			result[i].StartedAt = t.StartedAt.Time
+			result[i].FinishedAt = t.FinishedAt.Time
+		case s.State.Running != nil:
+			result[i].Phase = "Running"
+			result[i].StartedAt = s.State.Running.StartedAt.Time
+		case s.State.Waiting != nil:
+			result[i].Reason = s.State.Waiting.Reason
+		}
+	}
+	return result, nil
+}
+
+// GetInitContainerLogs returns the logs of the named init container of the
+// devbox pod.
+func (d *Devbox) GetInitContainerLogs(ctx context.Context, containerName string) (string, error) {
+	pod, err := d.getPod(ctx)
+	if err != nil {
+		return "", err
+	}
+	found := false
+	for _, c := range pod.Spec.InitContainers {
+		if c.Name == containerName {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return "", fmt.Errorf("init container %q not found in pod %s", containerName, pod.Name)
+	}
+
+	logs, err := d.sdk.client.Clientset().CoreV1().Pods(pod.Namespace).
+		GetLogs(pod.Name, &corev1.PodLogOptions{Container: containerName}).
+		DoRaw(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(logs), nil
+}
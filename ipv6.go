@@ -0,0 +1,37 @@
+package devbox
+
+import "context"
+
+const (
+	annotationIPv6Enabled = "devbox.sealos.run/ipv6-enabled"
+	annotationIPv6Address = "devbox.sealos.run/ipv6-address"
+)
+
+// EnableIPv6 asks the operator and CNI plugin to give the devbox a dual-stack
+// address. The address is reported by GetIPv6Address once assigned.
+func (d *Devbox) EnableIPv6(ctx context.Context) error {
+	return d.patchAnnotations(ctx, map[string]interface{}{
+		annotationIPv6Enabled: "true",
+	})
+}
+
+// DisableIPv6 withdraws a request made with EnableIPv6.
+func (d *Devbox) DisableIPv6(ctx context.Context) error {
+	return d.patchAnnotations(ctx, map[string]interface{}{
+		annotationIPv6Enabled: nil,
+	})
+}
+
+// GetIPv6Address returns the IPv6 address the operator assigned to the devbox.
+// It returns ErrIPv6NotEnabled if no address has been assigned, either because
+// IPv6 is not enabled or because assignment is still in progress.
+func (d *Devbox) GetIPv6Address(ctx context.Context) (string, error) {
+	if err := d.RefreshInfo(ctx); err != nil {
+		return "", err
+	}
+	addr := d.crd.Annotations[annotationIPv6Address]
+	if addr == "" {
+		return "", ErrIPv6NotEnabled
+	}
+	return addr, nil
+}
@@ -0,0 +1,77 @@
+package devbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/gitlayzer/devbox-sdk-go/api/v1alpha2"
+	"github.com/gitlayzer/devbox-sdk-go/devboxfake"
+)
+
+func TestIPv6(t *testing.T) {
+	ctx := context.Background()
+	sdk, srv := newFakeSDK(t, fakeDevbox("alice"))
+	d := newDevbox(srv.Get(devboxfake.Namespace, "alice"), sdk)
+
+	if _, err := d.GetIPv6Address(ctx); !errors.Is(err, ErrIPv6NotEnabled) {
+		t.Fatalf("GetIPv6Address before EnableIPv6: error = %v, want ErrIPv6NotEnabled", err)
+	}
+
+	rv := d.GetResourceVersion()
+	if err := d.EnableIPv6(ctx); err != nil {
+		t.Fatalf("EnableIPv6: %v", err)
+	}
+	if got := lastSentVersion(t, srv, "alice"); got != rv {
+		t.Errorf("EnableIPv6 sent resource version %q, want %q", got, rv)
+	}
+	if got := srv.Get(devboxfake.Namespace, "alice").Annotations[annotationIPv6Enabled]; got != "true" {
+		t.Fatalf("%s = %q after EnableIPv6, want %q", annotationIPv6Enabled, got, "true")
+	}
+	if _, err := d.GetIPv6Address(ctx); !errors.Is(err, ErrIPv6NotEnabled) {
+		t.Fatalf("GetIPv6Address before assignment: error = %v, want ErrIPv6NotEnabled", err)
+	}
+
+	// Assign an address, as the operator would.
+	crd := srv.Get(devboxfake.Namespace, "alice")
+	crd.Annotations[annotationIPv6Address] = "fd00::2a"
+	srv.Add(crd)
+
+	addr, err := d.GetIPv6Address(ctx)
+	if err != nil {
+		t.Fatalf("GetIPv6Address after assignment: %v", err)
+	}
+	if addr != "fd00::2a" {
+		t.Errorf("GetIPv6Address = %q, want %q", addr, "fd00::2a")
+	}
+
+	rv = d.GetResourceVersion()
+	if err := d.DisableIPv6(ctx); err != nil {
+		t.Fatalf("DisableIPv6: %v", err)
+	}
+	if got := lastSentVersion(t, srv, "alice"); got != rv {
+		t.Errorf("DisableIPv6 sent resource version %q, want %q", got, rv)
+	}
+	if _, ok := srv.Get(devboxfake.Namespace, "alice").Annotations[annotationIPv6Enabled]; ok {
+		t.Errorf("%s still set after DisableIPv6", annotationIPv6Enabled)
+	}
+}
+
+func TestIPv6Conflict(t *testing.T) {
+	ctx := context.Background()
+	sdk, srv := newFakeSDK(t, fakeDevbox("alice"))
+	d := newDevbox(srv.Get(devboxfake.Namespace, "alice"), sdk)
+
+	// A change made elsewhere makes the cached resource version stale.
+	if err := srv.SetPhase(devboxfake.Namespace, "alice", v1alpha2.DevboxPhasePending); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.EnableIPv6(ctx); !apierrors.IsConflict(err) {
+		t.Fatalf("EnableIPv6 of a stale devbox: error = %v, want a conflict", err)
+	}
+	if _, ok := srv.Get(devboxfake.Namespace, "alice").Annotations[annotationIPv6Enabled]; ok {
+		t.Errorf("EnableIPv6 of a stale devbox was applied")
+	}
+}
@@ -0,0 +1,46 @@
+package devbox
+
+import (
+	"context"
+	"os"
+)
+
+// GetSSHPrivateKeyPath writes the devbox private key to a new file in dir with
+// 0600 permissions and returns its path. If dir is empty, os.TempDir() is
+// used. The caller is responsible for removing the file; WithPrivateKeyFile
+// does so automatically.
+func (d *Devbox) GetSSHPrivateKeyPath(ctx context.Context, dir string) (string, error) {
+	keyPair, err := d.GetSSHKeyPair(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	// CreateTemp opens the file with 0600 permissions.
+	f, err := os.CreateTemp(dir, "devbox-"+d.crd.Name+"-key-")
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.WriteString(keyPair.PrivateKey); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// WithPrivateKeyFile writes the devbox private key to a temporary file, calls
+// fn with its path and removes the file once fn returns, whether or not fn
+// failed. It is meant for handing the key to external tools such as git or
+// rsync.
+func (d *Devbox) WithPrivateKeyFile(ctx context.Context, fn func(keyPath string) error) error {
+	path, err := d.GetSSHPrivateKeyPath(ctx, "")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(path)
+	return fn(path)
+}
@@ -0,0 +1,130 @@
+package devbox
+
+import (
+	"context"
+	"os"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/client-go/util/retry"
+)
+
+// kubeconfigTokenTTL is the lifetime requested for kubeconfig tokens. The API
+// server may shorten it.
+const kubeconfigTokenTTL = time.Hour
+
+// GenerateKubeconfig returns a kubeconfig that authenticates as the
+// ServiceAccount serviceAccountName in the SDK namespace, creating the
+// ServiceAccount if needed. The token is short-lived and cannot be renewed
+// from the kubeconfig; call GenerateKubeconfig again for a fresh one. The
+// ServiceAccount has no permissions until a RoleBinding grants them.
+func (s *DevboxSDK) GenerateKubeconfig(ctx context.Context, serviceAccountName string) (string, error) {
+	serviceAccounts := s.client.Clientset().CoreV1().ServiceAccounts(s.namespace)
+	_, err := serviceAccounts.Create(ctx, &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: serviceAccountName, Namespace: s.namespace},
+	}, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return "", err
+	}
+
+	ttl := int64(kubeconfigTokenTTL.Seconds())
+	token, err := serviceAccounts.CreateToken(ctx, serviceAccountName, &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{ExpirationSeconds: &ttl},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	caData := s.restConfig.CAData
+	if len(caData) == 0 && s.restConfig.CAFile != "" {
+		if caData, err = os.ReadFile(s.restConfig.CAFile); err != nil {
+			return "", err
+		}
+	}
+
+	const name = "devbox"
+	config := clientcmdapi.NewConfig()
+	config.Clusters[name] = &clientcmdapi.Cluster{
+		Server:                   s.restConfig.Host,
+		CertificateAuthorityData: caData,
+		InsecureSkipTLSVerify:    s.restConfig.Insecure,
+	}
+	config.AuthInfos[serviceAccountName] = &clientcmdapi.AuthInfo{Token: token.Status.Token}
+	config.Contexts[name] = &clientcmdapi.Context{
+		Cluster:   name,
+		AuthInfo:  serviceAccountName,
+		Namespace: s.namespace,
+	}
+	config.CurrentContext = name
+
+	data, err := clientcmd.Write(*config)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// RevokeKubeconfig deletes the ServiceAccount serviceAccountName and removes
+// it from the RoleBindings in the SDK namespace that bind it, which
+// invalidates every kubeconfig generated for it. Other subjects of a shared
+// RoleBinding keep their access; a RoleBinding is deleted only once it binds
+// no subject anymore.
+func (s *DevboxSDK) RevokeKubeconfig(ctx context.Context, serviceAccountName string) error {
+	roleBindings := s.client.Clientset().RbacV1().RoleBindings(s.namespace)
+	list, err := roleBindings.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, rb := range list.Items {
+		if len(s.otherSubjects(rb.Subjects, serviceAccountName)) == len(rb.Subjects) {
+			continue
+		}
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			current, err := roleBindings.Get(ctx, rb.Name, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			kept := s.otherSubjects(current.Subjects, serviceAccountName)
+			if len(kept) == len(current.Subjects) {
+				return nil
+			}
+			if len(kept) == 0 {
+				return roleBindings.Delete(ctx, current.Name, metav1.DeleteOptions{
+					Preconditions: &metav1.Preconditions{ResourceVersion: &current.ResourceVersion},
+				})
+			}
+			current.Subjects = kept
+			_, err = roleBindings.Update(ctx, current, metav1.UpdateOptions{})
+			return err
+		})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	err = s.client.Clientset().CoreV1().ServiceAccounts(s.namespace).Delete(ctx, serviceAccountName, metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// otherSubjects returns subjects without the ServiceAccount
+// serviceAccountName of the SDK namespace.
+func (s *DevboxSDK) otherSubjects(subjects []rbacv1.Subject, serviceAccountName string) []rbacv1.Subject {
+	var kept []rbacv1.Subject
+	for _, subject := range subjects {
+		if subject.Kind == rbacv1.ServiceAccountKind && subject.Name == serviceAccountName &&
+			(subject.Namespace == "" || subject.Namespace == s.namespace) {
+			continue
+		}
+		kept = append(kept, subject)
+	}
+	return kept
+}
@@ -0,0 +1,83 @@
+package devbox
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gitlayzer/devbox-sdk-go/api/v1alpha2"
+)
+
+// ListOptions filters and paginates devbox listings. Label and field
+// selectors use the Kubernetes syntax and are evaluated by the API server;
+// custom resources only support field selectors on metadata.name and
+// metadata.namespace. Phases is matched client-side, so a page can hold fewer
+// than Limit devboxes even when more remain.
+type ListOptions struct {
+	LabelSelector string
+	FieldSelector string
+	Phases        []v1alpha2.DevboxPhase
+	// Limit is the maximum number of devboxes fetched per request. Zero
+	// fetches everything at once.
+	Limit int64
+	// Continue resumes a listing from the token of a previous DevboxPage.
+	Continue string
+}
+
+// DevboxPage is one page of a devbox listing. Continue is empty on the last
+// page.
+type DevboxPage struct {
+	Devboxes []*Devbox
+	Continue string
+}
+
+// ListDevboxes returns the devboxes in the SDK namespace matching opts,
+// following continue tokens until the listing is complete. With a Limit, the
+// devboxes are fetched in chunks of that size, which bounds the size of each
+// API response.
+func (s *DevboxSDK) ListDevboxes(ctx context.Context, opts ListOptions) ([]*Devbox, error) {
+	var devboxes []*Devbox
+	for {
+		page, err := s.ListDevboxesPage(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		devboxes = append(devboxes, page.Devboxes...)
+		if page.Continue == "" {
+			return devboxes, nil
+		}
+		opts.Continue = page.Continue
+	}
+}
+
+// ListDevboxesPage returns a single page of the devboxes in the SDK namespace
+// matching opts. Pass the returned Continue token in opts to fetch the next
+// page; tokens expire after a few minutes, in which case the API server
+// returns a Gone error and the listing must start over.
+func (s *DevboxSDK) ListDevboxesPage(ctx context.Context, opts ListOptions) (*DevboxPage, error) {
+	list, err := s.client.List(ctx, metav1.ListOptions{
+		LabelSelector: opts.LabelSelector,
+		FieldSelector: opts.FieldSelector,
+		Limit:         opts.Limit,
+		Continue:      opts.Continue,
+	})
+	if err != nil {
+		return nil, apiError(err)
+	}
+
+	var keep func(*v1alpha2.Devbox) bool
+	if len(opts.Phases) > 0 {
+		keep = func(d *v1alpha2.Devbox) bool {
+			for _, phase := range opts.Phases {
+				if d.Status.Phase == phase {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	return &DevboxPage{
+		Devboxes: s.wrapList(list, keep),
+		Continue: list.Continue,
+	}, nil
+}
@@ -0,0 +1,104 @@
+package devbox
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxLoadTestErrors caps how many errors LoadTestResult keeps.
+const maxLoadTestErrors = 100
+
+// LoadTestResult summarizes a LoadTest run. ErrorRate is the fraction of
+// requests that failed; Errors holds at most the first 100 of them.
+type LoadTestResult struct {
+	P50               time.Duration
+	P95               time.Duration
+	P99               time.Duration
+	RequestsPerSecond float64
+	ErrorRate         float64
+	Errors            []error
+}
+
+// LoadTest calls RefreshInfo on the named devbox from concurrency goroutines
+// for the given duration and reports latency percentiles, throughput and
+// errors. It is meant for benchmarking the SDK and API server; every call is a
+// real API request.
+func (s *DevboxSDK) LoadTest(ctx context.Context, devboxName string, concurrency int, duration time.Duration) (*LoadTestResult, error) {
+	if concurrency <= 0 {
+		return nil, errors.New("concurrency must be positive")
+	}
+	crd, err := s.getDevbox(ctx, devboxName)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+
+	var (
+		mu        sync.Mutex
+		latencies []time.Duration
+		failures  int
+		errs      []error
+		wg        sync.WaitGroup
+	)
+	start := time.Now()
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Each worker has its own handle, as Devbox is not safe for
+			// concurrent use.
+			d := newDevbox(crd, s)
+			for ctx.Err() == nil {
+				begin := time.Now()
+				err := d.RefreshInfo(ctx)
+				elapsed := time.Since(begin)
+				if ctx.Err() != nil {
+					// Cut short by the end of the run; not a real failure.
+					return
+				}
+
+				mu.Lock()
+				latencies = append(latencies, elapsed)
+				if err != nil {
+					failures++
+					if len(errs) < maxLoadTestErrors {
+						errs = append(errs, err)
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	result := &LoadTestResult{Errors: errs}
+	if len(latencies) == 0 {
+		return result, nil
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	result.P50 = percentile(latencies, 0.50)
+	result.P95 = percentile(latencies, 0.95)
+	result.P99 = percentile(latencies, 0.99)
+	result.RequestsPerSecond = float64(len(latencies)) / elapsed.Seconds()
+	result.ErrorRate = float64(failures) / float64(len(latencies))
+	return result, nil
+}
+
+// percentile returns the p-th percentile of sorted, using the nearest-rank
+// method.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(p*float64(len(sorted))+0.5) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
@@ -0,0 +1,27 @@
+package devbox
+
+import "context"
+
+const annotationLocked = "devbox.sealos.run/locked"
+
+// Lock marks the devbox as locked, protecting it from destructive SDK
+// operations until Unlock is called: Delete, BatchDelete, ForceRecreate and
+// the recreation by SelfHeal return ErrDevboxLocked, and GarbageCollect skips
+// the devbox.
+func (d *Devbox) Lock(ctx context.Context) error {
+	return d.patchAnnotations(ctx, map[string]interface{}{
+		annotationLocked: "true",
+	})
+}
+
+// Unlock removes a lock set by Lock.
+func (d *Devbox) Unlock(ctx context.Context) error {
+	return d.patchAnnotations(ctx, map[string]interface{}{
+		annotationLocked: nil,
+	})
+}
+
+// IsLocked reports whether the devbox is locked, as of the last refresh.
+func (d *Devbox) IsLocked() bool {
+	return d.crd.Annotations[annotationLocked] == "true"
+}
@@ -0,0 +1,84 @@
+package devbox
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gitlayzer/devbox-sdk-go/api/v1alpha2"
+)
+
+// WithLogger makes the SDK log to logger: every Kubernetes request at debug
+// level, or warn level if it failed on the server or did not complete; every
+// check of a wait loop at debug level; and every requested state change at
+// info level. Records are logged with the context of the operation, so
+// handlers can add fields such as trace IDs from it.
+func WithLogger(logger *slog.Logger) DevboxSDKOption {
+	return func(s *DevboxSDK) {
+		s.log = logger
+		s.wrapTransport(func(rt http.RoundTripper) http.RoundTripper {
+			return &loggingTransport{logger: logger, next: rt}
+		})
+	}
+}
+
+// logAttrs logs to the logger set with WithLogger, if any.
+func (s *DevboxSDK) logAttrs(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	if s.log == nil {
+		return
+	}
+	s.log.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// logState logs a requested state change of the named devbox.
+func (s *DevboxSDK) logState(ctx context.Context, namespace, name string, state v1alpha2.DevboxState, err error) {
+	level := slog.LevelInfo
+	if err != nil {
+		level = slog.LevelWarn
+	}
+	s.logAttrs(ctx, level, "devbox state change",
+		slog.String("namespace", namespace),
+		slog.String("devbox", name),
+		slog.String("state", string(state)),
+		errorAttr(err))
+}
+
+// errorAttr returns an "error" attribute for err, or an empty attribute,
+// which handlers omit, if err is nil.
+func errorAttr(err error) slog.Attr {
+	if err == nil {
+		return slog.Attr{}
+	}
+	return slog.String("error", err.Error())
+}
+
+// loggingTransport logs every request with its outcome and duration.
+type loggingTransport struct {
+	logger *slog.Logger
+	next   http.RoundTripper
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("path", req.URL.Path),
+		slog.Duration("duration", time.Since(start)),
+	}
+	level := slog.LevelDebug
+	switch {
+	case err != nil:
+		level = slog.LevelWarn
+		attrs = append(attrs, errorAttr(err))
+	case resp.StatusCode >= http.StatusInternalServerError:
+		level = slog.LevelWarn
+		fallthrough
+	default:
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	}
+	t.logger.LogAttrs(req.Context(), level, "kubernetes request", attrs...)
+	return resp, err
+}
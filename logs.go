@@ -0,0 +1,60 @@
+package devbox
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// LogOptions configures Devbox.Logs.
+type LogOptions struct {
+	// Follow keeps the stream open and delivers new log lines as they are
+	// written, until the container stops or the context is cancelled.
+	Follow bool
+	// TailLines, if positive, starts the stream at that many lines from the
+	// end of the log.
+	TailLines int64
+	// Since, if positive, only returns lines written within that duration.
+	Since time.Duration
+	// Previous returns the logs of the previous instance of the container,
+	// which is where a crash on startup is explained.
+	Previous bool
+}
+
+// Logs streams the logs of the devbox container through the Kubernetes API.
+// The caller must close the returned stream.
+func (d *Devbox) Logs(ctx context.Context, opts LogOptions) (io.ReadCloser, error) {
+	pod, err := d.getPod(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c := d.mainContainer(pod)
+	if c == nil {
+		return nil, errors.New("no devbox container in pod " + pod.Name)
+	}
+
+	logOpts := &corev1.PodLogOptions{
+		Container: c.Name,
+		Follow:    opts.Follow,
+		Previous:  opts.Previous,
+	}
+	if opts.TailLines > 0 {
+		logOpts.TailLines = &opts.TailLines
+	}
+	if opts.Since > 0 {
+		// The API counts whole seconds and rejects zero.
+		seconds := int64((opts.Since + time.Second - 1) / time.Second)
+		logOpts.SinceSeconds = &seconds
+	}
+
+	stream, err := d.sdk.client.Clientset().CoreV1().Pods(pod.Namespace).
+		GetLogs(pod.Name, logOpts).
+		Stream(ctx)
+	if err != nil {
+		return nil, apiError(err)
+	}
+	return stream, nil
+}
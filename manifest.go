@@ -0,0 +1,89 @@
+package devbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/gitlayzer/devbox-sdk-go/api/v1alpha2"
+)
+
+// LayerDescriptor describes a single image layer.
+type LayerDescriptor struct {
+	Digest    string
+	MediaType string
+	Size      int64
+}
+
+// ImageConfig contains the runtime configuration baked into an image.
+type ImageConfig struct {
+	Architecture string
+	OS           string
+	Created      time.Time
+	User         string
+	WorkingDir   string
+	Entrypoint   []string
+	Cmd          []string
+	Env          []string
+	Labels       map[string]string
+}
+
+// ReleaseManifest is the parsed OCI manifest of a release's target image.
+type ReleaseManifest struct {
+	Image  string
+	Layers []LayerDescriptor
+	Config ImageConfig
+	// DigestMap maps each layer digest to its uncompressed diff ID.
+	DigestMap map[string]string
+}
+
+// GetReleaseManifest fetches the manifest and config of the release's target
+// image from its registry without pulling the layers. ctx bounds the registry
+// requests.
+func (r *Release) GetReleaseManifest(ctx context.Context) (*ReleaseManifest, error) {
+	if r.crd.Status.Phase != v1alpha2.DevBoxReleasePhaseReleased {
+		return nil, ErrReleaseNotFinished
+	}
+
+	img, err := remoteImage(ctx, r.TargetImage())
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := img.Manifest()
+	if err != nil {
+		return nil, err
+	}
+	configFile, err := img.ConfigFile()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReleaseManifest{
+		Image:     r.TargetImage(),
+		Layers:    make([]LayerDescriptor, len(manifest.Layers)),
+		DigestMap: make(map[string]string, len(manifest.Layers)),
+		Config: ImageConfig{
+			Architecture: configFile.Architecture,
+			OS:           configFile.OS,
+			Created:      configFile.Created.Time,
+			User:         configFile.Config.User,
+			WorkingDir:   configFile.Config.WorkingDir,
+			Entrypoint:   configFile.Config.Entrypoint,
+			Cmd:          configFile.Config.Cmd,
+			Env:          configFile.Config.Env,
+			Labels:       configFile.Config.Labels,
+		},
+	}
+	for i, layer := range manifest.Layers {
+		result.Layers[i] = LayerDescriptor{
+			Digest:    layer.Digest.String(),
+			MediaType: string(layer.MediaType),
+			Size:      layer.Size,
+		}
+		if i < len(configFile.RootFS.DiffIDs) {
+			result.DigestMap[layer.Digest.String()] = configFile.RootFS.DiffIDs[i].String()
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,108 @@
+package devbox
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// memoryCommand prints /proc/meminfo, a separator line and, if the kernel
+// supports PSI, /proc/pressure/memory.
+const memoryCommand = "cat /proc/meminfo; echo ---; cat /proc/pressure/memory 2>/dev/null || true"
+
+// MemoryPressure describes memory availability in the devbox. The PSI fields
+// are the percentage of time some task stalled on memory over the last 10, 60
+// and 300 seconds; PSIAvailable is false on kernels without PSI (before 4.20),
+// in which case they are zero.
+type MemoryPressure struct {
+	AvailableKiB int64
+	CachedKiB    int64
+	BuffersKiB   int64
+	PSI10s       float64
+	PSI60s       float64
+	PSI300s      float64
+	PSIAvailable bool
+}
+
+// GetMemoryPressure reads /proc/meminfo and /proc/pressure/memory inside the
+// devbox.
+func (d *Devbox) GetMemoryPressure(ctx context.Context) (*MemoryPressure, error) {
+	out, err := d.runSSH(ctx, memoryCommand, nil)
+	if err != nil {
+		return nil, err
+	}
+	meminfo, pressure, _ := bytes.Cut(out, []byte("---\n"))
+
+	result, err := parseMeminfo(meminfo)
+	if err != nil {
+		return nil, err
+	}
+	if err := parseMemoryPSI(pressure, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// parseMeminfo parses the fields of /proc/meminfo used by MemoryPressure.
+func parseMeminfo(data []byte) (*MemoryPressure, error) {
+	result := &MemoryPressure{}
+	fields := map[string]*int64{
+		"MemAvailable": &result.AvailableKiB,
+		"Cached":       &result.CachedKiB,
+		"Buffers":      &result.BuffersKiB,
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		name, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		dest, ok := fields[name]
+		if !ok {
+			continue
+		}
+		kib, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimSpace(value), " kB"), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected /proc/meminfo line: %q", scanner.Text())
+		}
+		*dest = kib
+	}
+	return result, scanner.Err()
+}
+
+// parseMemoryPSI parses the "some" line of /proc/pressure/memory, e.g.
+// "some avg10=0.00 avg60=0.00 avg300=0.00 total=0", into result.
+func parseMemoryPSI(data []byte, result *MemoryPressure) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || fields[0] != "some" {
+			continue
+		}
+		for _, f := range fields[1:] {
+			key, value, _ := strings.Cut(f, "=")
+			var dest *float64
+			switch key {
+			case "avg10":
+				dest = &result.PSI10s
+			case "avg60":
+				dest = &result.PSI60s
+			case "avg300":
+				dest = &result.PSI300s
+			default:
+				continue
+			}
+			v, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return fmt.Errorf("unexpected /proc/pressure/memory line: %q", scanner.Text())
+			}
+			*dest = v
+		}
+		result.PSIAvailable = true
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,123 @@
+package devbox
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	customMetricsKey = "devbox.sealos.run/custom-metrics"
+	maxMetricPoints  = 1000
+)
+
+// MetricPoint is a single value recorded with RecordMetric.
+type MetricPoint struct {
+	Name      string            `json:"name"`
+	Value     float64           `json:"value"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Timestamp time.Time         `json:"timestamp"`
+}
+
+// RecordMetric appends a custom metric point to the devbox metrics ConfigMap.
+// The ConfigMap keeps the most recent 1000 points across all metric names and
+// is deleted together with the devbox.
+func (d *Devbox) RecordMetric(ctx context.Context, name string, value float64, labels map[string]string) error {
+	point := MetricPoint{
+		Name:      name,
+		Value:     value,
+		Labels:    copyStringMap(labels),
+		Timestamp: time.Now().UTC(),
+	}
+	configMaps := d.sdk.client.Clientset().CoreV1().ConfigMaps(d.crd.Namespace)
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := configMaps.Get(ctx, d.metricsConfigMapName(), metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			cm = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            d.metricsConfigMapName(),
+					Namespace:       d.crd.Namespace,
+					Labels:          map[string]string{labelDevboxName: d.crd.Name},
+					OwnerReferences: []metav1.OwnerReference{d.ownerReference()},
+				},
+			}
+		} else if err != nil {
+			return err
+		}
+
+		points, err := metricPoints(cm)
+		if err != nil {
+			return err
+		}
+		points = append(points, point)
+		if len(points) > maxMetricPoints {
+			points = points[len(points)-maxMetricPoints:]
+		}
+		data, err := json.Marshal(points)
+		if err != nil {
+			return err
+		}
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[customMetricsKey] = string(data)
+
+		if cm.ResourceVersion == "" {
+			_, err = configMaps.Create(ctx, cm, metav1.CreateOptions{})
+			if apierrors.IsAlreadyExists(err) {
+				// Created concurrently; retry as an update.
+				return apierrors.NewConflict(corev1.Resource("configmaps"), cm.Name, err)
+			}
+			return err
+		}
+		_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// GetMetrics returns the points recorded for the named metric since the given
+// time, oldest first.
+func (d *Devbox) GetMetrics(ctx context.Context, name string, since time.Time) ([]MetricPoint, error) {
+	cm, err := d.sdk.client.Clientset().CoreV1().ConfigMaps(d.crd.Namespace).Get(ctx, d.metricsConfigMapName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	points, err := metricPoints(cm)
+	if err != nil {
+		return nil, err
+	}
+	var result []MetricPoint
+	for _, p := range points {
+		if p.Name == name && !p.Timestamp.Before(since) {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+func (d *Devbox) metricsConfigMapName() string {
+	return d.crd.Name + "-metrics"
+}
+
+// metricPoints decodes the points stored in the metrics ConfigMap.
+func metricPoints(cm *corev1.ConfigMap) ([]MetricPoint, error) {
+	data := cm.Data[customMetricsKey]
+	if data == "" {
+		return nil, nil
+	}
+	var points []MetricPoint
+	if err := json.Unmarshal([]byte(data), &points); err != nil {
+		return nil, err
+	}
+	return points, nil
+}
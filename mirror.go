@@ -0,0 +1,100 @@
+package devbox
+
+import (
+	"context"
+
+	"github.com/gitlayzer/devbox-sdk-go/api/v1alpha2"
+	"github.com/gitlayzer/devbox-sdk-go/client"
+)
+
+// runtimeAnnotations are the annotations that describe the state of one
+// particular devbox rather than its configuration: locks and pins, access
+// records, TLS and IPv6 assignments, the jump host setup whose key Secret
+// belongs to the devbox, and the last-applied configuration of kubectl. They
+// are dropped whenever a devbox is copied or exported.
+var runtimeAnnotations = []string{
+	annotationLocked,
+	annotationPinnedDigest,
+	annotationLastSDKAccess,
+	annotationTLSSecretName,
+	annotationTLSExpiresAt,
+	annotationIPv6Address,
+	annotationSSHJumpHost,
+	annotationSSHJumpUser,
+	annotationSSHJumpKeySecret,
+	annotationSSHJumpHostKey,
+	"kubectl.kubernetes.io/last-applied-configuration",
+}
+
+// configAnnotations returns a copy of annotations without the runtime
+// annotations.
+func configAnnotations(annotations map[string]string) map[string]string {
+	copied := copyStringMap(annotations)
+	for _, key := range runtimeAnnotations {
+		delete(copied, key)
+	}
+	return copied
+}
+
+// MirrorDevbox creates a copy of the named devbox in destNamespace under the
+// same name. Only the spec, labels and annotations are copied, without the
+// annotations that describe the source's runtime state such as its lock; the
+// new devbox starts without any runtime status. The mirror is recorded in its
+// provenance.
+func (s *DevboxSDK) MirrorDevbox(ctx context.Context, sourceName, destNamespace string) (*Devbox, error) {
+	source, err := s.getDevbox(ctx, sourceName)
+	if err != nil {
+		return nil, err
+	}
+
+	dest, err := s.forNamespace(destNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	mirror := &v1alpha2.Devbox{}
+	mirror.Name = source.Name
+	mirror.Namespace = destNamespace
+	mirror.Labels = copyStringMap(source.Labels)
+	mirror.Annotations = configAnnotations(source.Annotations)
+	mirror.Spec = *source.Spec.DeepCopy()
+
+	provenance, err := s.appendProvenance(source.Annotations, ProvenanceEvent{
+		Action:        "Mirror",
+		SourceDevbox:  source.Namespace + "/" + source.Name,
+		SourceVersion: source.ResourceVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if mirror.Annotations == nil {
+		mirror.Annotations = map[string]string{}
+	}
+	mirror.Annotations[annotationProvenance] = provenance
+
+	created, err := dest.client.Create(ctx, mirror)
+	s.metrics.recordCreation(err)
+	if err != nil {
+		return nil, err
+	}
+	dest.cache.Set(created.Name, created)
+	return newDevbox(created, dest), nil
+}
+
+// forNamespace returns a copy of the SDK bound to namespace, with its own
+// client and cache.
+func (s *DevboxSDK) forNamespace(namespace string) (*DevboxSDK, error) {
+	if namespace == s.namespace {
+		return s, nil
+	}
+	c, err := client.New(s.restConfig, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	scoped := *s
+	scoped.namespace = namespace
+	scoped.client = c
+	scoped.cache = newDevboxCache()
+	return &scoped, nil
+}
@@ -0,0 +1,104 @@
+package devbox
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// MountedSecret describes a Secret mounted as a volume into the devbox.
+// Items lists the projected keys; it is empty when all keys are mounted.
+type MountedSecret struct {
+	SecretName string
+	MountPath  string
+	Items      []string
+}
+
+// MountedConfigMap describes a ConfigMap mounted as a volume into the devbox.
+// Items lists the projected keys; it is empty when all keys are mounted.
+type MountedConfigMap struct {
+	ConfigMapName string
+	MountPath     string
+	Items         []string
+}
+
+// GetMountedSecrets returns the Secret volumes of the devbox pod with the path
+// they are mounted at in the devbox container. The path is empty for volumes
+// only mounted into other containers.
+func (d *Devbox) GetMountedSecrets(ctx context.Context) ([]MountedSecret, error) {
+	pod, err := d.getPod(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var secrets []MountedSecret
+	for _, v := range pod.Spec.Volumes {
+		if v.Secret == nil {
+			continue
+		}
+		secrets = append(secrets, MountedSecret{
+			SecretName: v.Secret.SecretName,
+			MountPath:  d.mountPath(pod, v.Name),
+			Items:      keyPaths(v.Secret.Items),
+		})
+	}
+	return secrets, nil
+}
+
+// GetMountedConfigMaps returns the ConfigMap volumes of the devbox pod with the
+// path they are mounted at in the devbox container. The path is empty for
+// volumes only mounted into other containers.
+func (d *Devbox) GetMountedConfigMaps(ctx context.Context) ([]MountedConfigMap, error) {
+	pod, err := d.getPod(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var configMaps []MountedConfigMap
+	for _, v := range pod.Spec.Volumes {
+		if v.ConfigMap == nil {
+			continue
+		}
+		configMaps = append(configMaps, MountedConfigMap{
+			ConfigMapName: v.ConfigMap.Name,
+			MountPath:     d.mountPath(pod, v.Name),
+			Items:         keyPaths(v.ConfigMap.Items),
+		})
+	}
+	return configMaps, nil
+}
+
+// mainContainer returns the devbox container spec of pod.
+func (d *Devbox) mainContainer(pod *corev1.Pod) *corev1.Container {
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == d.crd.Name {
+			return &pod.Spec.Containers[i]
+		}
+	}
+	if len(pod.Spec.Containers) == 1 {
+		return &pod.Spec.Containers[0]
+	}
+	return nil
+}
+
+// mountPath returns where the named volume is mounted in the devbox container.
+func (d *Devbox) mountPath(pod *corev1.Pod, volume string) string {
+	c := d.mainContainer(pod)
+	if c == nil {
+		return ""
+	}
+	for _, m := range c.VolumeMounts {
+		if m.Name == volume {
+			return m.MountPath
+		}
+	}
+	return ""
+}
+
+func keyPaths(items []corev1.KeyToPath) []string {
+	keys := make([]string, len(items))
+	for i, item := range items {
+		keys[i] = item.Key
+	}
+	return keys
+}
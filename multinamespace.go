@@ -0,0 +1,56 @@
+package devbox
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/gitlayzer/devbox-sdk-go/api/v1alpha2"
+)
+
+// Namespace returns a copy of the SDK bound to namespace, so that calls can
+// target another namespace:
+//
+//	teamA, err := sdk.Namespace("team-a")
+//	if err != nil {
+//		return err
+//	}
+//	d, err := teamA.GetDevbox(ctx, "alice")
+//
+// The copy has its own client and cache.
+func (s *DevboxSDK) Namespace(namespace string) (*DevboxSDK, error) {
+	return s.forNamespace(namespace)
+}
+
+// ListDevboxesAllNamespaces returns the devboxes of every namespace. It needs
+// permission to list devboxes cluster-wide. Each devbox is bound to a copy of
+// the SDK for its namespace, as returned by Namespace.
+func (s *DevboxSDK) ListDevboxesAllNamespaces(ctx context.Context) ([]*Devbox, error) {
+	// The typed client is bound to a namespace, so list through the dynamic
+	// client instead.
+	obj, err := s.dynamic.Resource(devboxResource).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, apiError(err)
+	}
+	list := &v1alpha2.DevboxList{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), list); err != nil {
+		return nil, err
+	}
+
+	scoped := map[string]*DevboxSDK{}
+	devboxes := make([]*Devbox, 0, len(list.Items))
+	for i := range list.Items {
+		crd := &list.Items[i]
+		sdk, ok := scoped[crd.Namespace]
+		if !ok {
+			if sdk, err = s.forNamespace(crd.Namespace); err != nil {
+				return nil, err
+			}
+			scoped[crd.Namespace] = sdk
+		}
+		sdk.cache.Set(crd.Name, crd)
+		devboxes = append(devboxes, newDevbox(crd, sdk))
+	}
+	return devboxes, nil
+}
@@ -0,0 +1,149 @@
+package devbox
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsclient "k8s.io/metrics/pkg/client/clientset/versioned"
+)
+
+// NamespaceSummary aggregates resource figures for all devboxes in a namespace.
+// CPU is in cores and memory in GB.
+type NamespaceSummary struct {
+	Namespace        string
+	Total            int
+	ByPhase          map[string]int
+	CPULimit         float64
+	MemoryLimit      float64
+	CPUUsage         float64
+	MemoryUsage      float64
+	TopCPUDevbox     string
+	MetricsAvailable bool
+}
+
+// GetNamespaceSummary aggregates limits and current usage across all devboxes
+// in the SDK namespace. Usage is read from the metrics API with a single list
+// of the namespace's pod metrics, matched to devboxes through their pods;
+// devboxes without metrics (e.g. stopped ones) count as zero.
+func (s *DevboxSDK) GetNamespaceSummary(ctx context.Context) (*NamespaceSummary, error) {
+	list, err := s.client.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &NamespaceSummary{
+		Namespace: s.namespace,
+		Total:     len(list.Items),
+		ByPhase:   make(map[string]int),
+	}
+	devboxes := make(map[string]bool, len(list.Items))
+	for i := range list.Items {
+		d := newDevbox(&list.Items[i], s)
+		devboxes[d.Name()] = true
+		summary.ByPhase[d.Status()]++
+		summary.CPULimit += d.CPULimit()
+		summary.MemoryLimit += d.MemoryLimit()
+	}
+
+	usage, err := s.usageByDevbox(ctx, devboxes)
+	if err != nil {
+		// Without metrics-server the summary still has the limits.
+		return summary, nil
+	}
+	summary.MetricsAvailable = true
+
+	var topCPU float64
+	for _, item := range list.Items {
+		u, ok := usage[item.Name]
+		if !ok {
+			continue
+		}
+		summary.CPUUsage += u.cpu
+		summary.MemoryUsage += u.memory
+		if u.cpu > topCPU {
+			topCPU = u.cpu
+			summary.TopCPUDevbox = item.Name
+		}
+	}
+
+	return summary, nil
+}
+
+// usageTotals is the CPU (cores) and memory (GB) usage of a pod.
+type usageTotals struct {
+	cpu, memory float64
+}
+
+// usageByDevbox returns the usage of the live pods in the SDK namespace that
+// back one of devboxes, keyed by devbox name. It lists the pods and their
+// metrics once each and joins them on the pod's app label.
+func (s *DevboxSDK) usageByDevbox(ctx context.Context, devboxes map[string]bool) (map[string]usageTotals, error) {
+	metrics, err := metricsclient.NewForConfig(s.restConfig)
+	if err != nil {
+		return nil, err
+	}
+	podMetrics, err := metrics.MetricsV1beta1().PodMetricses(s.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	pods, err := s.client.Clientset().CoreV1().Pods(s.namespace).List(ctx, metav1.ListOptions{LabelSelector: labelAppName})
+	if err != nil {
+		return nil, err
+	}
+
+	podDevbox := make(map[string]string, len(pods.Items))
+	for _, pod := range pods.Items {
+		if name := pod.Labels[labelAppName]; pod.DeletionTimestamp == nil && devboxes[name] {
+			podDevbox[pod.Name] = name
+		}
+	}
+	usage := map[string]usageTotals{}
+	for i := range podMetrics.Items {
+		name, ok := podDevbox[podMetrics.Items[i].Name]
+		if !ok {
+			continue
+		}
+		u := podUsage(&podMetrics.Items[i])
+		total := usage[name]
+		total.cpu += u.cpu
+		total.memory += u.memory
+		usage[name] = total
+	}
+	return usage, nil
+}
+
+// GetResourceUsage returns the current CPU (cores) and memory (GB) usage of
+// the running devbox from the metrics API, which requires metrics-server.
+func (d *Devbox) GetResourceUsage(ctx context.Context) (cpu, memory float64, err error) {
+	metrics, err := metricsclient.NewForConfig(d.sdk.restConfig)
+	if err != nil {
+		return 0, 0, err
+	}
+	return d.resourceUsage(ctx, metrics)
+}
+
+// resourceUsage returns the current CPU (cores) and memory (GB) usage of the
+// devbox pod from the metrics API.
+func (d *Devbox) resourceUsage(ctx context.Context, metrics metricsclient.Interface) (float64, float64, error) {
+	pod, err := d.getPod(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	podMetrics, err := metrics.MetricsV1beta1().PodMetricses(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+	if err != nil {
+		return 0, 0, err
+	}
+	u := podUsage(podMetrics)
+	return u.cpu, u.memory, nil
+}
+
+// podUsage sums the usage of the containers in podMetrics.
+func podUsage(podMetrics *metricsv1beta1.PodMetrics) usageTotals {
+	var u usageTotals
+	for _, c := range podMetrics.Containers {
+		u.cpu += float64(c.Usage.Cpu().MilliValue()) / 1000
+		u.memory += float64(c.Usage.Memory().Value()) / (1024 * 1024 * 1024)
+	}
+	return u
+}
@@ -0,0 +1,157 @@
+package devbox
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// labelNamespaceName is set on every namespace by Kubernetes.
+const labelNamespaceName = "kubernetes.io/metadata.name"
+
+// PolicyDirection is the direction of traffic a NetworkPolicyRule allows.
+type PolicyDirection string
+
+const (
+	PolicyIngress PolicyDirection = "Ingress"
+	PolicyEgress  PolicyDirection = "Egress"
+)
+
+// NetworkPolicyRule allows traffic in one direction on the given ports, to or
+// from the given CIDRs and namespaces. Empty Ports allow all ports; empty CIDRs
+// and AllowNamespaces allow all peers.
+type NetworkPolicyRule struct {
+	Direction       PolicyDirection
+	Ports           []int32
+	CIDRs           []string
+	AllowNamespaces []string
+}
+
+// GetNetworkPolicies returns the rules of all NetworkPolicies that belong to
+// the devbox.
+func (d *Devbox) GetNetworkPolicies(ctx context.Context) ([]NetworkPolicyRule, error) {
+	policies, err := d.sdk.client.Clientset().NetworkingV1().NetworkPolicies(d.crd.Namespace).List(ctx, d.networkPolicySelector())
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []NetworkPolicyRule
+	for _, policy := range policies.Items {
+		for _, r := range policy.Spec.Ingress {
+			rules = append(rules, policyRule(PolicyIngress, r.Ports, r.From))
+		}
+		for _, r := range policy.Spec.Egress {
+			rules = append(rules, policyRule(PolicyEgress, r.Ports, r.To))
+		}
+	}
+	return rules, nil
+}
+
+// SetNetworkPolicy replaces the NetworkPolicy of the devbox with one made of
+// rules. The policy is owned by the devbox and deleted along with it.
+func (d *Devbox) SetNetworkPolicy(ctx context.Context, rules []NetworkPolicyRule) error {
+	policy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            d.crd.Name + "-policy",
+			Namespace:       d.crd.Namespace,
+			Labels:          map[string]string{labelDevboxName: d.crd.Name},
+			OwnerReferences: []metav1.OwnerReference{d.ownerReference()},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{labelAppName: d.crd.Name}},
+		},
+	}
+
+	var hasIngress, hasEgress bool
+	for _, r := range rules {
+		ports, peers := r.policyPorts(), r.policyPeers()
+		switch r.Direction {
+		case PolicyIngress:
+			hasIngress = true
+			policy.Spec.Ingress = append(policy.Spec.Ingress, networkingv1.NetworkPolicyIngressRule{Ports: ports, From: peers})
+		case PolicyEgress:
+			hasEgress = true
+			policy.Spec.Egress = append(policy.Spec.Egress, networkingv1.NetworkPolicyEgressRule{Ports: ports, To: peers})
+		}
+	}
+	if hasIngress {
+		policy.Spec.PolicyTypes = append(policy.Spec.PolicyTypes, networkingv1.PolicyTypeIngress)
+	}
+	if hasEgress {
+		policy.Spec.PolicyTypes = append(policy.Spec.PolicyTypes, networkingv1.PolicyTypeEgress)
+	}
+
+	policies := d.sdk.client.Clientset().NetworkingV1().NetworkPolicies(d.crd.Namespace)
+	existing, err := policies.Get(ctx, policy.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = policies.Create(ctx, policy, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	policy.ResourceVersion = existing.ResourceVersion
+	_, err = policies.Update(ctx, policy, metav1.UpdateOptions{})
+	return err
+}
+
+// ClearNetworkPolicies deletes all NetworkPolicies that belong to the devbox.
+func (d *Devbox) ClearNetworkPolicies(ctx context.Context) error {
+	return d.sdk.client.Clientset().NetworkingV1().NetworkPolicies(d.crd.Namespace).
+		DeleteCollection(ctx, metav1.DeleteOptions{}, d.networkPolicySelector())
+}
+
+func (d *Devbox) networkPolicySelector() metav1.ListOptions {
+	return metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{labelDevboxName: d.crd.Name}).String(),
+	}
+}
+
+func (r NetworkPolicyRule) policyPorts() []networkingv1.NetworkPolicyPort {
+	var ports []networkingv1.NetworkPolicyPort
+	for _, p := range r.Ports {
+		port := intstr.FromInt(int(p))
+		protocol := corev1.ProtocolTCP
+		ports = append(ports, networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &port})
+	}
+	return ports
+}
+
+func (r NetworkPolicyRule) policyPeers() []networkingv1.NetworkPolicyPeer {
+	var peers []networkingv1.NetworkPolicyPeer
+	for _, cidr := range r.CIDRs {
+		peers = append(peers, networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: cidr}})
+	}
+	for _, ns := range r.AllowNamespaces {
+		peers = append(peers, networkingv1.NetworkPolicyPeer{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{labelNamespaceName: ns}},
+		})
+	}
+	return peers
+}
+
+// policyRule converts a Kubernetes policy rule back into a NetworkPolicyRule.
+func policyRule(direction PolicyDirection, ports []networkingv1.NetworkPolicyPort, peers []networkingv1.NetworkPolicyPeer) NetworkPolicyRule {
+	rule := NetworkPolicyRule{Direction: direction}
+	for _, p := range ports {
+		if p.Port != nil && p.Port.Type == intstr.Int {
+			rule.Ports = append(rule.Ports, p.Port.IntVal)
+		}
+	}
+	for _, peer := range peers {
+		if peer.IPBlock != nil {
+			rule.CIDRs = append(rule.CIDRs, peer.IPBlock.CIDR)
+		}
+		if peer.NamespaceSelector != nil {
+			if ns, ok := peer.NamespaceSelector.MatchLabels[labelNamespaceName]; ok {
+				rule.AllowNamespaces = append(rule.AllowNamespaces, ns)
+			}
+		}
+	}
+	return rule
+}
@@ -0,0 +1,156 @@
+package devbox
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// kernLogOOMCommand prints the kernel log lines about OOM kills, if the log
+// exists and is readable.
+const kernLogOOMCommand = "grep -iE 'out of memory|oom-kill' /var/log/kern.log 2>/dev/null || true"
+
+// OOMEvent is an out-of-memory kill in the devbox. RequestedMemoryBytes is the
+// memory request of the container at the time GetOOMHistory was called, or
+// zero if unknown.
+type OOMEvent struct {
+	Timestamp            time.Time
+	ContainerName        string
+	Reason               string
+	Message              string
+	RequestedMemoryBytes int64
+}
+
+// GetOOMHistory returns the OOM kills of the devbox, newest first. They are
+// collected from the last termination state of its containers, the
+// OOMKilling events of its node that name the pod's cgroup and, if it can be
+// read over SSH, /var/log/kern.log. The same kill may therefore appear more
+// than once, from different sources. Node events are written by
+// node-problem-detector in the default namespace; they are skipped if the SDK
+// may not list events there.
+func (d *Devbox) GetOOMHistory(ctx context.Context) ([]OOMEvent, error) {
+	pod, err := d.getPod(ctx)
+	if err != nil {
+		return nil, err
+	}
+	requested := make(map[string]int64)
+	for _, c := range pod.Spec.Containers {
+		if mem, ok := c.Resources.Requests[corev1.ResourceMemory]; ok {
+			requested[c.Name] = mem.Value()
+		}
+	}
+	mainName := d.crd.Name
+	if c := d.mainContainer(pod); c != nil {
+		mainName = c.Name
+	}
+
+	history := []OOMEvent{}
+
+	if pod.Spec.NodeName != "" {
+		selector := fields.Set{
+			"involvedObject.kind": "Node",
+			"involvedObject.name": pod.Spec.NodeName,
+			"reason":              "OOMKilling",
+		}.AsSelector().String()
+		events, err := d.sdk.client.Clientset().CoreV1().Events(metav1.NamespaceDefault).List(ctx, metav1.ListOptions{FieldSelector: selector})
+		if err != nil && !apierrors.IsForbidden(err) {
+			return nil, err
+		}
+		if err == nil {
+			for _, e := range events.Items {
+				if !mentionsPod(e.Message, pod) {
+					continue
+				}
+				event := devboxEvent(&e)
+				history = append(history, OOMEvent{
+					Timestamp:            event.LastSeen,
+					ContainerName:        mainName,
+					Reason:               event.Reason,
+					Message:              event.Message,
+					RequestedMemoryBytes: requested[mainName],
+				})
+			}
+		}
+	}
+
+	for _, s := range pod.Status.ContainerStatuses {
+		for _, t := range []*corev1.ContainerStateTerminated{s.State.Terminated, s.LastTerminationState.Terminated} {
+			if t == nil || t.Reason != "OOMKilled" {
+				continue
+			}
+			history = append(history, OOMEvent{
+				Timestamp:            t.FinishedAt.Time,
+				ContainerName:        s.Name,
+				Reason:               t.Reason,
+				Message:              t.Message,
+				RequestedMemoryBytes: requested[s.Name],
+			})
+		}
+	}
+
+	// The kernel log is optional; images without it or without SSH access
+	// only report what Kubernetes saw.
+	if out, err := d.runSSH(ctx, kernLogOOMCommand, nil); err == nil {
+		for _, e := range parseKernLogOOM(out, time.Now()) {
+			e.ContainerName = mainName
+			e.RequestedMemoryBytes = requested[mainName]
+			history = append(history, e)
+		}
+	}
+
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].Timestamp.After(history[j].Timestamp)
+	})
+	return history, nil
+}
+
+// mentionsPod reports whether a kernel OOM message concerns pod, by looking
+// for the pod UID in the memory cgroup path it names. The systemd cgroup
+// driver writes the UID with underscores instead of dashes.
+func mentionsPod(message string, pod *corev1.Pod) bool {
+	uid := string(pod.UID)
+	return uid != "" && (strings.Contains(message, uid) || strings.Contains(message, strings.ReplaceAll(uid, "-", "_")))
+}
+
+// parseKernLogOOM parses syslog-formatted kernel log lines such as
+// "Oct 16 14:08:27 host kernel: Out of memory: Killed process 42 (java)".
+// Syslog omits the year, so the year of now is assumed, or the previous year
+// for timestamps that would lie in the future.
+func parseKernLogOOM(data []byte, now time.Time) []OOMEvent {
+	var events []OOMEvent
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if len(line) < len(time.Stamp) {
+			continue
+		}
+		ts, err := time.ParseInLocation(time.Stamp, line[:len(time.Stamp)], now.Location())
+		if err != nil {
+			continue
+		}
+		ts = ts.AddDate(now.Year(), 0, 0)
+		if ts.After(now) {
+			ts = ts.AddDate(-1, 0, 0)
+		}
+
+		message := line
+		if _, rest, ok := strings.Cut(line, "kernel: "); ok {
+			message = rest
+		}
+		events = append(events, OOMEvent{
+			Timestamp: ts,
+			Reason:    "KernelOOM",
+			Message:   message,
+		})
+	}
+	return events
+}
@@ -0,0 +1,241 @@
+package devbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/gitlayzer/devbox-sdk-go/api/v1alpha2"
+	"github.com/gitlayzer/devbox-sdk-go/types"
+)
+
+// defaultFieldManager identifies the SDK in the managed fields of objects it
+// applies server-side, unless WithFieldManager sets another name.
+const defaultFieldManager = "devbox-sdk-go"
+
+// WithFieldManager sets the field manager name under which the SDK applies
+// changes server-side, so that the owner of each field can be told apart when
+// several applications manage the same devboxes.
+func WithFieldManager(name string) DevboxSDKOption {
+	return func(s *DevboxSDK) {
+		s.fieldManager = name
+	}
+}
+
+// managerName returns the field manager name of the SDK.
+func (s *DevboxSDK) managerName() string {
+	if s.fieldManager != "" {
+		return s.fieldManager
+	}
+	return defaultFieldManager
+}
+
+// Apply reads the devbox, lets mutate change a copy of its spec and writes
+// back only the fields mutate changed, as a merge patch recorded under the
+// field manager of the SDK. Fields mutate leaves alone are not written, so
+// they keep whatever value other clients give them. The patch carries the
+// resource version that was read; if the devbox changes in between, it is
+// read again and mutate is called again on the fresh spec, so mutate must be
+// safe to call more than once.
+func (d *Devbox) Apply(ctx context.Context, mutate func(*v1alpha2.DevboxSpec)) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := d.RefreshInfo(ctx); err != nil {
+			return err
+		}
+		original, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&d.crd.Spec)
+		if err != nil {
+			return err
+		}
+		spec := d.crd.Spec.DeepCopy()
+		mutate(spec)
+		modified, err := runtime.DefaultUnstructuredConverter.ToUnstructured(spec)
+		if err != nil {
+			return err
+		}
+		changes := mergePatchFor(original, modified)
+		if len(changes) == 0 {
+			return nil
+		}
+
+		data, err := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{"resourceVersion": d.crd.ResourceVersion},
+			"spec":     changes,
+		})
+		if err != nil {
+			return err
+		}
+		patched, err := d.sdk.dynamic.Resource(devboxResource).Namespace(d.crd.Namespace).
+			Patch(ctx, d.crd.Name, k8stypes.MergePatchType, data, metav1.PatchOptions{FieldManager: d.sdk.managerName()})
+		if err != nil {
+			return apiError(err)
+		}
+		return d.setUnstructured(patched)
+	})
+}
+
+// mergePatchFor returns the JSON merge patch that turns original into
+// modified: changed and added fields with their new value, removed fields as
+// nil, and nested objects recursively.
+func mergePatchFor(original, modified map[string]interface{}) map[string]interface{} {
+	patch := map[string]interface{}{}
+	for key, value := range modified {
+		old, ok := original[key]
+		if ok && reflect.DeepEqual(old, value) {
+			continue
+		}
+		oldMap, oldIsMap := old.(map[string]interface{})
+		newMap, newIsMap := value.(map[string]interface{})
+		if oldIsMap && newIsMap {
+			patch[key] = mergePatchFor(oldMap, newMap)
+			continue
+		}
+		patch[key] = value
+	}
+	for key := range original {
+		if _, ok := modified[key]; !ok {
+			patch[key] = nil
+		}
+	}
+	return patch
+}
+
+// patch applies a JSON merge patch to the devbox and updates the cached CRD.
+// The cached resource version is included so that the API server rejects the
+// patch with a ConflictError if the devbox changed since it was last read.
+// The patch also records the access for GarbageCollect when due.
+func (d *Devbox) patch(ctx context.Context, patch map[string]interface{}) error {
+	metadata, _ := patch["metadata"].(map[string]interface{})
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+		patch["metadata"] = metadata
+	}
+	metadata["resourceVersion"] = d.crd.ResourceVersion
+	if d.accessStale() {
+		annotations, _ := metadata["annotations"].(map[string]interface{})
+		if annotations == nil {
+			annotations = map[string]interface{}{}
+			metadata["annotations"] = annotations
+		}
+		annotations[annotationLastSDKAccess] = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	updated, err := d.sdk.client.Patch(ctx, d.crd.Name, k8stypes.MergePatchType, data)
+	if err != nil {
+		return apiError(err)
+	}
+	d.crd = updated
+	d.sdk.cache.Set(d.crd.Name, updated)
+	return nil
+}
+
+// apply server-side applies the spec built by build to the devbox under
+// manager and updates the cached CRD. The manager takes ownership of the
+// applied fields, overriding conflicting managers. Fields it applied earlier
+// but that are missing from the spec are removed, so each caller must use its
+// own manager and always apply every field it manages.
+//
+// The apply carries the resource version of the cached CRD, so it fails if
+// the devbox changed since it was read; the devbox is then read again and the
+// spec rebuilt from the fresh state, as with Apply.
+func (d *Devbox) apply(ctx context.Context, manager string, build func() map[string]interface{}) error {
+	stale := false
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if stale {
+			if err := d.RefreshInfo(ctx); err != nil {
+				return err
+			}
+		}
+		stale = true
+
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": v1alpha2.GroupVersion.String(),
+			"kind":       "Devbox",
+			"metadata": map[string]interface{}{
+				"name":            d.crd.Name,
+				"namespace":       d.crd.Namespace,
+				"resourceVersion": d.crd.ResourceVersion,
+			},
+			"spec": build(),
+		}}
+		applied, err := d.sdk.dynamic.Resource(devboxResource).Namespace(d.crd.Namespace).
+			Apply(ctx, d.crd.Name, obj, metav1.ApplyOptions{FieldManager: manager, Force: true})
+		if err != nil {
+			return apiError(err)
+		}
+		return d.setUnstructured(applied)
+	})
+}
+
+// setUnstructured replaces the cached CRD with obj, as returned by the
+// dynamic client.
+func (d *Devbox) setUnstructured(obj *unstructured.Unstructured) error {
+	updated := &v1alpha2.Devbox{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, updated); err != nil {
+		return err
+	}
+	d.crd = updated
+	d.sdk.cache.Set(d.crd.Name, updated)
+	return nil
+}
+
+// patchConfig merge-patches fields under spec.config.
+func (d *Devbox) patchConfig(ctx context.Context, config map[string]interface{}) error {
+	return d.patch(ctx, map[string]interface{}{
+		"spec": map[string]interface{}{
+			"config": config,
+		},
+	})
+}
+
+// whileStopped runs fn while the devbox is stopped or paused. A running devbox
+// yields ErrDevboxRunning unless force is set, in which case it is stopped for
+// the duration of fn and started again afterwards, even if fn fails; a failure
+// to start it again is joined to the error of fn.
+func (d *Devbox) whileStopped(ctx context.Context, force bool, fn func() error) error {
+	if err := d.RefreshInfo(ctx); err != nil {
+		return err
+	}
+	if d.isStoppedOrPaused() {
+		return fn()
+	}
+	if !force {
+		return ErrDevboxRunning
+	}
+
+	if err := d.Stop(ctx); err != nil {
+		return err
+	}
+	if err := d.waitUntil(ctx, types.WaitForReadyOptions{}, "waiting for devbox to stop", d.isStoppedOrPaused); err != nil {
+		return err
+	}
+	if err := fn(); err != nil {
+		if startErr := d.Start(ctx); startErr != nil {
+			return errors.Join(err, fmt.Errorf("devbox %s left stopped: %w", d.crd.Name, startErr))
+		}
+		return err
+	}
+	return d.Start(ctx)
+}
+
+// patchAnnotations merge-patches the devbox annotations. A nil value removes
+// the annotation.
+func (d *Devbox) patchAnnotations(ctx context.Context, annotations map[string]interface{}) error {
+	return d.patch(ctx, map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": annotations,
+		},
+	})
+}
@@ -0,0 +1,54 @@
+package devbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gitlayzer/devbox-sdk-go/api/v1alpha2"
+	"github.com/gitlayzer/devbox-sdk-go/devboxfake"
+)
+
+func TestPatchSendsResourceVersion(t *testing.T) {
+	ctx := context.Background()
+	sdk, srv := newFakeSDK(t, fakeDevbox("alice"))
+	d := newDevbox(srv.Get(devboxfake.Namespace, "alice"), sdk)
+
+	rv := d.GetResourceVersion()
+	if err := d.patchAnnotations(ctx, map[string]interface{}{"example.com/first": "1"}); err != nil {
+		t.Fatalf("patch of a fresh devbox: %v", err)
+	}
+	if got := lastSentVersion(t, srv, "alice"); got != rv {
+		t.Errorf("patch sent resource version %q, want %q", got, rv)
+	}
+	if got, want := d.GetResourceVersion(), srv.Get(devboxfake.Namespace, "alice").ResourceVersion; got != want {
+		t.Errorf("resource version after patch = %s, want %s", got, want)
+	}
+
+	// A change made elsewhere makes the cached resource version stale.
+	if err := srv.SetPhase(devboxfake.Namespace, "alice", v1alpha2.DevboxPhasePending); err != nil {
+		t.Fatal(err)
+	}
+	rv = d.GetResourceVersion()
+	err := d.patchAnnotations(ctx, map[string]interface{}{"example.com/second": "2"})
+	if got := lastSentVersion(t, srv, "alice"); got != rv {
+		t.Errorf("patch of a stale devbox sent resource version %q, want %q", got, rv)
+	}
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("patch of a stale devbox: error = %v, want a ConflictError", err)
+	}
+	if _, ok := srv.Get(devboxfake.Namespace, "alice").Annotations["example.com/second"]; ok {
+		t.Error("patch of a stale devbox was applied")
+	}
+
+	if err := d.RefreshInfo(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.patchAnnotations(ctx, map[string]interface{}{"example.com/second": "2"}); err != nil {
+		t.Fatalf("patch after RefreshInfo: %v", err)
+	}
+	if got := srv.Get(devboxfake.Namespace, "alice").Annotations["example.com/second"]; got != "2" {
+		t.Errorf("annotation after retried patch = %q, want %q", got, "2")
+	}
+}
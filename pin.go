@@ -0,0 +1,87 @@
+package devbox
+
+import (
+	"context"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+const annotationPinnedDigest = "devbox.sealos.run/pinned-digest"
+
+// PatchImageOptions configures PatchImage.
+type PatchImageOptions struct {
+	// IgnorePin allows changing the image of a pinned devbox. The pin is
+	// kept.
+	IgnorePin bool
+}
+
+// ValidateImage checks that image exists in its registry and returns its
+// digest. Only the manifest headers are fetched.
+func ValidateImage(ctx context.Context, image string) (string, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return "", err
+	}
+	desc, err := remote.Head(ref,
+		remote.WithContext(ctx),
+		remote.WithAuthFromKeychain(authn.DefaultKeychain),
+	)
+	if err != nil {
+		return "", err
+	}
+	return desc.Digest.String(), nil
+}
+
+// PatchImage changes the runtime image of the devbox after checking that it
+// exists. It returns ErrDevboxPinned if the devbox is pinned, unless
+// opts.IgnorePin is set.
+func (d *Devbox) PatchImage(ctx context.Context, image string, opts PatchImageOptions) error {
+	if err := d.RefreshInfo(ctx); err != nil {
+		return err
+	}
+	if d.IsPinned() && !opts.IgnorePin {
+		return ErrDevboxPinned
+	}
+	if _, err := ValidateImage(ctx, image); err != nil {
+		return err
+	}
+	return d.patch(ctx, map[string]interface{}{
+		"spec": map[string]interface{}{"image": image},
+	})
+}
+
+// PinToImage records the current digest of the devbox image, protecting the
+// devbox from image changes through PatchImage until UnpinFromImage is
+// called.
+func (d *Devbox) PinToImage(ctx context.Context) error {
+	if err := d.RefreshInfo(ctx); err != nil {
+		return err
+	}
+	digest, err := ValidateImage(ctx, d.Image())
+	if err != nil {
+		return err
+	}
+	return d.patchAnnotations(ctx, map[string]interface{}{
+		annotationPinnedDigest: digest,
+	})
+}
+
+// UnpinFromImage removes a pin set by PinToImage.
+func (d *Devbox) UnpinFromImage(ctx context.Context) error {
+	return d.patchAnnotations(ctx, map[string]interface{}{
+		annotationPinnedDigest: nil,
+	})
+}
+
+// IsPinned reports whether the devbox is pinned, as of the last refresh.
+func (d *Devbox) IsPinned() bool {
+	return d.crd.Annotations[annotationPinnedDigest] != ""
+}
+
+// PinnedDigest returns the image digest recorded by PinToImage, or an empty
+// string if the devbox is not pinned.
+func (d *Devbox) PinnedDigest() string {
+	return d.crd.Annotations[annotationPinnedDigest]
+}
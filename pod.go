@@ -0,0 +1,122 @@
+package devbox
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const labelAppName = "app.kubernetes.io/name"
+
+// getPod returns the pod currently backing the devbox.
+func (d *Devbox) getPod(ctx context.Context) (*corev1.Pod, error) {
+	pods, err := d.sdk.client.Clientset().CoreV1().Pods(d.crd.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{labelAppName: d.crd.Name}).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range pods.Items {
+		if pods.Items[i].DeletionTimestamp == nil {
+			return &pods.Items[i], nil
+		}
+	}
+	return nil, errors.New("no pod found for devbox " + d.crd.Name)
+}
+
+// mainContainerStatus returns the status of the devbox container in pod.
+func (d *Devbox) mainContainerStatus(pod *corev1.Pod) (*corev1.ContainerStatus, error) {
+	for i := range pod.Status.ContainerStatuses {
+		if pod.Status.ContainerStatuses[i].Name == d.crd.Name {
+			return &pod.Status.ContainerStatuses[i], nil
+		}
+	}
+	if len(pod.Status.ContainerStatuses) == 1 {
+		return &pod.Status.ContainerStatuses[0], nil
+	}
+	return nil, ErrContainerNotRunning
+}
+
+// GetContainerID returns the container runtime ID of the devbox container,
+// e.g. "containerd://<id>".
+func (d *Devbox) GetContainerID(ctx context.Context) (string, error) {
+	pod, err := d.getPod(ctx)
+	if err != nil {
+		return "", err
+	}
+	status, err := d.mainContainerStatus(pod)
+	if err != nil {
+		return "", err
+	}
+	if status.ContainerID == "" {
+		return "", ErrContainerNotRunning
+	}
+	return status.ContainerID, nil
+}
+
+// GetLastRestartTime returns when the devbox container last restarted and how
+// many times it has restarted in total. The time is nil if it never restarted.
+func (d *Devbox) GetLastRestartTime(ctx context.Context) (*time.Time, int32, error) {
+	pod, err := d.getPod(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	status, err := d.mainContainerStatus(pod)
+	if err != nil {
+		return nil, 0, err
+	}
+	if status.RestartCount == 0 {
+		return nil, 0, nil
+	}
+
+	if t := status.LastTerminationState.Terminated; t != nil && !t.FinishedAt.IsZero() {
+		restarted := t.FinishedAt.Time
+		return &restarted, status.RestartCount, nil
+	}
+	if r := status.State.Running; r != nil {
+		restarted := r.StartedAt.Time
+		return &restarted, status.RestartCount, nil
+	}
+	return nil, status.RestartCount, nil
+}
+
+// GetPodReference returns a reference to the pod currently backing the devbox.
+func (d *Devbox) GetPodReference(ctx context.Context) (*corev1.ObjectReference, error) {
+	pod, err := d.getPod(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.ObjectReference{
+		Kind:            "Pod",
+		APIVersion:      "v1",
+		Namespace:       pod.Namespace,
+		Name:            pod.Name,
+		UID:             pod.UID,
+		ResourceVersion: pod.ResourceVersion,
+	}, nil
+}
+
+// GetTerminationMessage returns the termination message of the devbox
+// container, i.e. the tail of its output when it last exited.
+func (d *Devbox) GetTerminationMessage(ctx context.Context) (string, error) {
+	pod, err := d.getPod(ctx)
+	if err != nil {
+		return "", err
+	}
+	status, err := d.mainContainerStatus(pod)
+	if err != nil {
+		return "", err
+	}
+
+	if t := status.State.Terminated; t != nil {
+		return t.Message, nil
+	}
+	if t := status.LastTerminationState.Terminated; t != nil {
+		return t.Message, nil
+	}
+	return "", ErrContainerRunning
+}
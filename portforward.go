@@ -0,0 +1,180 @@
+package devbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// PortForwarder is an active port forward from a local port to a port inside
+// the devbox.
+type PortForwarder struct {
+	localPort int
+	done      chan struct{}
+	once      sync.Once
+	stop      func()
+}
+
+// LocalPort returns the local port being forwarded, which is useful when
+// PortForward was called with port 0.
+func (f *PortForwarder) LocalPort() int {
+	return f.localPort
+}
+
+// Done returns a channel that is closed once the forward has stopped.
+func (f *PortForwarder) Done() <-chan struct{} {
+	return f.done
+}
+
+// Close stops the forward and closes its connections.
+func (f *PortForwarder) Close() error {
+	f.once.Do(func() {
+		f.stop()
+		close(f.done)
+	})
+	return nil
+}
+
+// PortForward forwards connections to localPort on 127.0.0.1 to remotePort
+// inside the devbox until ctx is cancelled or the returned forwarder is
+// closed. A localPort of 0 picks a free port. The forward is tunneled over
+// SSH; if the devbox cannot be reached over SSH, it falls back to the
+// Kubernetes port-forward API, which needs the pods/portforward permission.
+func (d *Devbox) PortForward(ctx context.Context, localPort, remotePort int) (*PortForwarder, error) {
+	var f *PortForwarder
+	client, err := d.SSHDial(ctx)
+	if err == nil {
+		f, err = forwardSSH(client, localPort, remotePort)
+	} else {
+		f, err = d.forwardAPI(ctx, localPort, remotePort)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			f.Close()
+		case <-f.done:
+		}
+	}()
+	return f, nil
+}
+
+// forwardSSH forwards each local connection through a direct-tcpip channel of
+// client.
+func forwardSSH(client *ssh.Client, localPort, remotePort int) (*PortForwarder, error) {
+	listener, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(localPort)))
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	f := &PortForwarder{
+		localPort: listener.Addr().(*net.TCPAddr).Port,
+		done:      make(chan struct{}),
+		stop: func() {
+			listener.Close()
+			client.Close()
+		},
+	}
+
+	remoteAddr := net.JoinHostPort("127.0.0.1", strconv.Itoa(remotePort))
+	go func() {
+		defer f.Close()
+		for {
+			local, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer local.Close()
+				remote, err := client.Dial("tcp", remoteAddr)
+				if err != nil {
+					return
+				}
+				defer remote.Close()
+				pipe(local, remote)
+			}()
+		}
+	}()
+	return f, nil
+}
+
+// pipe copies between a and b in both directions until either side is done.
+func pipe(a, b io.ReadWriter) {
+	done := make(chan struct{}, 2)
+	go func() {
+		io.Copy(a, b)
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(b, a)
+		done <- struct{}{}
+	}()
+	<-done
+}
+
+// forwardAPI forwards through the port-forward subresource of the devbox pod.
+func (d *Devbox) forwardAPI(ctx context.Context, localPort, remotePort int) (*PortForwarder, error) {
+	pod, err := d.getPod(ctx)
+	if err != nil {
+		return nil, err
+	}
+	transport, upgrader, err := spdy.RoundTripperFor(d.sdk.restConfig)
+	if err != nil {
+		return nil, err
+	}
+	req := d.sdk.client.Clientset().CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("portforward")
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, req.URL())
+
+	stopCh, readyCh := make(chan struct{}), make(chan struct{})
+	forwarder, err := portforward.NewOnAddresses(dialer, []string{"127.0.0.1"},
+		[]string{fmt.Sprintf("%d:%d", localPort, remotePort)}, stopCh, readyCh, io.Discard, io.Discard)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &PortForwarder{
+		done: make(chan struct{}),
+		stop: func() { close(stopCh) },
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- forwarder.ForwardPorts()
+		f.Close()
+	}()
+
+	select {
+	case <-readyCh:
+	case err := <-errCh:
+		if err == nil {
+			err = errors.New("port forward stopped before it was ready")
+		}
+		return nil, err
+	case <-ctx.Done():
+		f.Close()
+		return nil, ctx.Err()
+	}
+
+	ports, err := forwarder.GetPorts()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	f.localPort = int(ports[0].Local)
+	return f, nil
+}
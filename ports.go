@@ -0,0 +1,39 @@
+package devbox
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ReplacePortsOptions controls how port replacements are applied.
+type ReplacePortsOptions struct {
+	// Restart starts the devbox again once the new ports are patched.
+	Restart bool
+}
+
+// ReplacePorts replaces the container ports of the devbox. The devbox must be
+// stopped or paused, since port changes require the pod to be recreated.
+func (d *Devbox) ReplacePorts(ctx context.Context, ports []corev1.ContainerPort, opts ReplacePortsOptions) error {
+	return d.replaceConfigPorts(ctx, "ports", ports, opts)
+}
+
+// ReplaceAppPorts replaces the app (service) ports of the devbox. The devbox
+// must be stopped or paused, since port changes require the pod to be recreated.
+func (d *Devbox) ReplaceAppPorts(ctx context.Context, ports []corev1.ServicePort, opts ReplacePortsOptions) error {
+	return d.replaceConfigPorts(ctx, "appPorts", ports, opts)
+}
+
+func (d *Devbox) replaceConfigPorts(ctx context.Context, field string, ports interface{}, opts ReplacePortsOptions) error {
+	err := d.whileStopped(ctx, false, func() error {
+		return d.patchConfig(ctx, map[string]interface{}{field: ports})
+	})
+	if err != nil {
+		return err
+	}
+
+	if opts.Restart {
+		return d.Start(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package devbox
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// psCommand lists all processes with the columns parsePS expects, after a
+// header line. It sticks to columns that both procps and busybox support;
+// the processes are sorted by topProcesses.
+const psCommand = "ps -e -o pid,user,pcpu,pmem,vsz,rss,etime,args"
+
+// ProcessInfo describes a process running in the devbox. VSZ and RSS are in
+// bytes.
+type ProcessInfo struct {
+	PID        int
+	User       string
+	Command    string
+	CPUPercent float64
+	MemPercent float64
+	VSZ        int64
+	RSS        int64
+	Elapsed    time.Duration
+}
+
+// GetTopProcesses returns the n processes using the most CPU in the devbox,
+// busiest first. It returns ErrSSHUnavailable if the devbox cannot be reached.
+func (d *Devbox) GetTopProcesses(ctx context.Context, n int) ([]ProcessInfo, error) {
+	out, err := d.runSSH(ctx, psCommand, nil)
+	if err != nil {
+		return nil, err
+	}
+	processes, err := parsePS(out)
+	if err != nil {
+		return nil, err
+	}
+	return topProcesses(processes, n), nil
+}
+
+// topProcesses sorts processes by CPU usage, busiest first, and returns the
+// first n of them, or all of them if n is negative.
+func topProcesses(processes []ProcessInfo, n int) []ProcessInfo {
+	sort.SliceStable(processes, func(i, j int) bool {
+		return processes[i].CPUPercent > processes[j].CPUPercent
+	})
+	if n >= 0 && n < len(processes) {
+		processes = processes[:n]
+	}
+	return processes
+}
+
+// parsePS parses the output of psCommand.
+func parsePS(data []byte) ([]ProcessInfo, error) {
+	var processes []ProcessInfo
+
+	header := true
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if header {
+			header = false
+			continue
+		}
+		if len(fields) < 8 {
+			return nil, fmt.Errorf("unexpected ps line: %q", line)
+		}
+
+		pid, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("unexpected ps line: %q", line)
+		}
+		cpu, err1 := strconv.ParseFloat(fields[2], 64)
+		mem, err2 := strconv.ParseFloat(fields[3], 64)
+		vsz, err3 := strconv.ParseInt(fields[4], 10, 64)
+		rss, err4 := strconv.ParseInt(fields[5], 10, 64)
+		elapsed, err5 := parseElapsed(fields[6])
+		for _, err := range []error{err1, err2, err3, err4, err5} {
+			if err != nil {
+				return nil, fmt.Errorf("unexpected ps line: %q", line)
+			}
+		}
+
+		processes = append(processes, ProcessInfo{
+			PID:        pid,
+			User:       fields[1],
+			Command:    strings.Join(fields[7:], " "),
+			CPUPercent: cpu,
+			MemPercent: mem,
+			VSZ:        vsz * 1024,
+			RSS:        rss * 1024,
+			Elapsed:    elapsed,
+		})
+	}
+	return processes, scanner.Err()
+}
+
+// parseElapsed parses an etime column of ps, in the form [[dd-]hh:]mm:ss.
+// Busybox lets the minutes exceed 59 instead of printing hours.
+func parseElapsed(s string) (time.Duration, error) {
+	var days int64
+	if d, rest, ok := strings.Cut(s, "-"); ok {
+		n, err := strconv.ParseInt(d, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		days, s = n, rest
+	}
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid elapsed time %q", s)
+	}
+	var seconds int64
+	for _, part := range parts {
+		n, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		seconds = seconds*60 + n
+	}
+	return time.Duration(days*24*60*60+seconds) * time.Second, nil
+}
@@ -0,0 +1,132 @@
+package devbox
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const psHeader = "  PID USER     %CPU %MEM    VSZ   RSS     ELAPSED COMMAND\n"
+
+func TestParsePS(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []ProcessInfo
+		wantErr bool
+	}{
+		{
+			name: "procps",
+			data: psHeader +
+				"      1 root      0.0  0.1   4364  3300  1-00:00:00 /bin/sh -c sleep infinity\n" +
+				"    812 devbox   12.5  3.2 812340 65536       02:05 go run ./cmd/server --port 8080\n" +
+				"    900 devbox    0.3  0.0   2000   100    01:02:03 sleep 3600\n",
+			want: []ProcessInfo{
+				{PID: 1, User: "root", Command: "/bin/sh -c sleep infinity", MemPercent: 0.1,
+					VSZ: 4364 * 1024, RSS: 3300 * 1024, Elapsed: 24 * time.Hour},
+				{PID: 812, User: "devbox", Command: "go run ./cmd/server --port 8080", CPUPercent: 12.5, MemPercent: 3.2,
+					VSZ: 812340 * 1024, RSS: 65536 * 1024, Elapsed: 125 * time.Second},
+				{PID: 900, User: "devbox", Command: "sleep 3600", CPUPercent: 0.3,
+					VSZ: 2000 * 1024, RSS: 100 * 1024, Elapsed: time.Hour + 2*time.Minute + 3*time.Second},
+			},
+		},
+		{
+			name: "busybox minutes past the hour",
+			data: "PID   USER     %CPU %MEM VSZ  RSS  ELAPSED COMMAND\n" +
+				"   42 devbox 1.0 0.5 100 50 125:07 sleep 60\n",
+			want: []ProcessInfo{
+				{PID: 42, User: "devbox", Command: "sleep 60", CPUPercent: 1, MemPercent: 0.5,
+					VSZ: 100 * 1024, RSS: 50 * 1024, Elapsed: 125*time.Minute + 7*time.Second},
+			},
+		},
+		{name: "empty", data: ""},
+		{name: "header only", data: "\n" + psHeader},
+		{name: "first line is the header", data: "42 devbox 1.0 0.5 100 50 00:07 sleep 60\n"},
+		{name: "missing command", data: psHeader + "42 devbox 1.0 0.5 100 50 00:07\n", wantErr: true},
+		{name: "non-numeric cpu", data: psHeader + "42 devbox high 0.5 100 50 00:07 sleep 60\n", wantErr: true},
+		{name: "elapsed seconds", data: psHeader + "42 devbox 1.0 0.5 100 50 7 sleep 60\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePS([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePS() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsePS() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTopProcesses(t *testing.T) {
+	processes := func() []ProcessInfo {
+		return []ProcessInfo{
+			{PID: 1, CPUPercent: 0.5},
+			{PID: 2, CPUPercent: 30},
+			{PID: 3, CPUPercent: 2},
+			{PID: 4, CPUPercent: 30},
+		}
+	}
+	tests := []struct {
+		name string
+		n    int
+		want []int
+	}{
+		{name: "all", n: -1, want: []int{2, 4, 3, 1}},
+		{name: "top two", n: 2, want: []int{2, 4}},
+		{name: "more than there are", n: 10, want: []int{2, 4, 3, 1}},
+		{name: "none", n: 0, want: []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []int{}
+			for _, p := range topProcesses(processes(), tt.n) {
+				got = append(got, p.PID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("topProcesses(%d) PIDs = %v, want %v", tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetTopProcesses(t *testing.T) {
+	ctx := context.Background()
+	d := newDevbox(fakeDevbox("alice"), &DevboxSDK{})
+	commands := fakeSSH(t, func(cmd string, stdout, stderr io.Writer) uint32 {
+		if cmd != psCommand {
+			io.WriteString(stderr, "unexpected command")
+			return 127
+		}
+		io.WriteString(stdout, psHeader+
+			"  1 root   0.0 0.1 4364 3300 10:00 /bin/sh\n"+
+			" 20 devbox 7.5 1.0 9000 4000 01:00 node server.js\n"+
+			" 30 devbox 42.0 2.0 9000 4000 00:10 go build ./...\n")
+		return 0
+	})
+
+	processes, err := d.GetTopProcesses(ctx, 2)
+	if err != nil {
+		t.Fatalf("GetTopProcesses: %v", err)
+	}
+	var pids []int
+	for _, p := range processes {
+		pids = append(pids, p.PID)
+	}
+	if want := []int{30, 20}; !reflect.DeepEqual(pids, want) {
+		t.Errorf("GetTopProcesses(2) PIDs = %v, want %v", pids, want)
+	}
+	if got := commands(); len(got) != 1 || strings.Contains(got[0], "--sort") {
+		t.Errorf("commands = %q, want a single portable ps command", got)
+	}
+
+	unavailableSSH(t)
+	if _, err := d.GetTopProcesses(ctx, 2); !errors.Is(err, ErrSSHUnavailable) {
+		t.Errorf("GetTopProcesses without SSH: error = %v, want ErrSSHUnavailable", err)
+	}
+}
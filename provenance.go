@@ -0,0 +1,86 @@
+package devbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	annotationProvenance = "devbox.sealos.run/provenance"
+	maxProvenanceEvents  = 50
+)
+
+// ProvenanceEvent records one step in the lineage of a devbox, such as being
+// mirrored or cloned from another devbox. Timestamp is in RFC 3339 format.
+type ProvenanceEvent struct {
+	Timestamp     string `json:"timestamp"`
+	Actor         string `json:"actor,omitempty"`
+	Action        string `json:"action"`
+	SourceDevbox  string `json:"sourceDevbox,omitempty"`
+	SourceVersion string `json:"sourceVersion,omitempty"`
+}
+
+// GetCreationProvenanceChain returns the lineage recorded on the devbox,
+// oldest event first. Devboxes created from another devbox inherit its chain.
+func (d *Devbox) GetCreationProvenanceChain(ctx context.Context) ([]ProvenanceEvent, error) {
+	if err := d.RefreshInfo(ctx); err != nil {
+		return nil, err
+	}
+	return provenanceChain(d.crd.Annotations)
+}
+
+// AppendProvenance adds an event to the lineage of the devbox, keeping the
+// most recent 50 events. Timestamp and Actor are filled in if empty.
+func (d *Devbox) AppendProvenance(ctx context.Context, event ProvenanceEvent) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := d.RefreshInfo(ctx); err != nil {
+			return err
+		}
+		value, err := d.sdk.appendProvenance(d.crd.Annotations, event)
+		if err != nil {
+			return err
+		}
+		return d.patchAnnotations(ctx, map[string]interface{}{annotationProvenance: value})
+	})
+}
+
+// appendProvenance returns the provenance annotation value for annotations
+// with event appended.
+func (s *DevboxSDK) appendProvenance(annotations map[string]string, event ProvenanceEvent) (string, error) {
+	chain, err := provenanceChain(annotations)
+	if err != nil {
+		return "", err
+	}
+	if event.Timestamp == "" {
+		event.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	}
+	if event.Actor == "" {
+		event.Actor = s.auditUser()
+	}
+
+	chain = append(chain, event)
+	if len(chain) > maxProvenanceEvents {
+		chain = chain[len(chain)-maxProvenanceEvents:]
+	}
+	data, err := json.Marshal(chain)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func provenanceChain(annotations map[string]string) ([]ProvenanceEvent, error) {
+	raw := annotations[annotationProvenance]
+	if raw == "" {
+		return nil, nil
+	}
+	var chain []ProvenanceEvent
+	if err := json.Unmarshal([]byte(raw), &chain); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", annotationProvenance, err)
+	}
+	return chain, nil
+}
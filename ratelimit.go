@@ -0,0 +1,51 @@
+package devbox
+
+import (
+	"fmt"
+	"net/http"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/rest"
+)
+
+// RateLimiter returns a copy of the SDK whose Kubernetes requests, including
+// list and watch calls, are throttled to rps requests per second with the
+// given burst. The copy shares the cache with s, but has clients of its own,
+// which are released with it. rps must be positive and burst at least 1;
+// RateLimiter panics otherwise, as no request could ever be sent.
+func (s *DevboxSDK) RateLimiter(rps float64, burst int) *DevboxSDK {
+	if rps <= 0 || burst < 1 {
+		panic(fmt.Sprintf("devbox: invalid rate limit of %v requests per second with burst %d", rps, burst))
+	}
+	limiter := rate.NewLimiter(rate.Limit(rps), burst)
+
+	cfg := rest.CopyConfig(s.restConfig)
+	cfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &rateLimitedTransport{limiter: limiter, next: rt}
+	})
+
+	// The config only differs from the one the clients of s were built from
+	// by its transport wrapper, so building clients from it cannot fail.
+	c, dc, err := newClients(cfg, s.namespace)
+	if err != nil {
+		panic(fmt.Sprintf("devbox: building rate-limited clients: %v", err))
+	}
+
+	limited := *s
+	limited.restConfig = cfg
+	limited.client, limited.dynamic = c, dc
+	return &limited
+}
+
+// rateLimitedTransport waits on a limiter before every request.
+type rateLimitedTransport struct {
+	limiter *rate.Limiter
+	next    http.RoundTripper
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return t.next.RoundTrip(req)
+}
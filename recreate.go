@@ -0,0 +1,54 @@
+package devbox
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/gitlayzer/devbox-sdk-go/api/v1alpha2"
+	"github.com/gitlayzer/devbox-sdk-go/types"
+)
+
+// recreate deletes the devbox and creates it again from the same spec. If
+// beforeCreate is non-nil, it runs once the old devbox is gone. It returns
+// ErrDevboxLocked if the devbox is locked, and a *RecreateError for failures
+// after the devbox was deleted.
+func (d *Devbox) recreate(ctx context.Context, beforeCreate func() error) (*Devbox, error) {
+	fresh := &v1alpha2.Devbox{}
+	fresh.Name = d.crd.Name
+	fresh.Namespace = d.crd.Namespace
+	fresh.Labels = copyStringMap(d.crd.Labels)
+	fresh.Annotations = copyStringMap(d.crd.Annotations)
+	fresh.Spec = *d.crd.Spec.DeepCopy()
+
+	if err := d.Delete(ctx); err != nil {
+		return nil, err
+	}
+	if err := d.waitDeleted(ctx); err != nil {
+		return nil, &RecreateError{Name: fresh.Name, Err: err}
+	}
+	if beforeCreate != nil {
+		if err := beforeCreate(); err != nil {
+			return nil, &RecreateError{Name: fresh.Name, Err: err}
+		}
+	}
+
+	created, err := d.sdk.client.Create(ctx, fresh)
+	if err != nil {
+		return nil, &RecreateError{Name: fresh.Name, Err: apiError(err)}
+	}
+	d.sdk.cache.Set(created.Name, created)
+	return newDevbox(created, d.sdk), nil
+}
+
+// waitDeleted polls until the devbox no longer exists, for at most the
+// default wait timeout.
+func (d *Devbox) waitDeleted(ctx context.Context) error {
+	return d.sdk.pollWithBackoff(ctx, types.WaitForReadyOptions{}, "waiting for devbox "+d.crd.Name+" to be deleted", func() (bool, error) {
+		_, err := d.sdk.client.Get(ctx, d.crd.Name)
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	})
+}
@@ -0,0 +1,118 @@
+package devbox
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/gitlayzer/devbox-sdk-go/api/v1alpha2"
+	"github.com/gitlayzer/devbox-sdk-go/types"
+)
+
+// ReleaseWaitOptions configures Release.WaitForCompletion. A zero Timeout
+// defaults to 30 minutes, as committing and pushing an image takes longer
+// than a devbox takes to start.
+type ReleaseWaitOptions struct {
+	types.WaitForReadyOptions
+	// OnPhaseChange, if set, is called with the old and new phase whenever
+	// the phase of the release changes while waiting.
+	OnPhaseChange func(from, to string)
+}
+
+// Delete deletes the release. The image it pushed stays in the registry.
+// Deleting a release that no longer exists is not an error.
+func (r *Release) Delete(ctx context.Context) error {
+	err := r.sdk.releaseClient(r.namespace()).Delete(ctx, r.crd.Name, metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return apiError(err)
+}
+
+// PruneReleases deletes all but the keepLast newest releases of the devbox,
+// by creation time, and returns the names of the deleted releases.
+func (d *Devbox) PruneReleases(ctx context.Context, keepLast int) ([]string, error) {
+	releases, err := d.ListReleases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if keepLast < 0 {
+		keepLast = 0
+	}
+	if len(releases) <= keepLast {
+		return nil, nil
+	}
+
+	sort.SliceStable(releases, func(i, j int) bool {
+		return releases[i].crd.CreationTimestamp.After(releases[j].crd.CreationTimestamp.Time)
+	})
+	var deleted []string
+	for _, r := range releases[keepLast:] {
+		if err := r.Delete(ctx); err != nil {
+			return deleted, err
+		}
+		deleted = append(deleted, r.Name())
+	}
+	return deleted, nil
+}
+
+// WaitForCompletion polls the release, with exponential backoff by default,
+// until its target image has been pushed. It returns an error matching
+// ErrReleaseFailed if the release fails.
+func (r *Release) WaitForCompletion(ctx context.Context, opts ReleaseWaitOptions) error {
+	if opts.Timeout == 0 {
+		opts.Timeout = 30 * time.Minute
+	}
+	phase := r.Phase()
+	return r.sdk.pollWithBackoff(ctx, opts.WaitForReadyOptions, "waiting for release "+r.crd.Name, func() (bool, error) {
+		if err := r.refresh(ctx); err != nil {
+			return false, err
+		}
+		if r.Phase() != phase {
+			if opts.OnPhaseChange != nil {
+				opts.OnPhaseChange(phase, r.Phase())
+			}
+			phase = r.Phase()
+		}
+		switch r.crd.Status.Phase {
+		case v1alpha2.DevBoxReleasePhaseReleased:
+			return true, nil
+		case v1alpha2.DevBoxReleasePhaseFailed:
+			return false, fmt.Errorf("release %s: %w", r.crd.Name, ErrReleaseFailed)
+		}
+		return false, nil
+	})
+}
+
+// refresh reloads the release from the API server.
+func (r *Release) refresh(ctx context.Context) error {
+	obj, err := r.sdk.releaseClient(r.namespace()).Get(ctx, r.crd.Name, metav1.GetOptions{})
+	if err != nil {
+		return apiError(err)
+	}
+	updated := &v1alpha2.DevBoxRelease{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, updated); err != nil {
+		return err
+	}
+	r.crd = updated
+	return nil
+}
+
+func (r *Release) namespace() string {
+	if r.crd.Namespace != "" {
+		return r.crd.Namespace
+	}
+	return r.sdk.namespace
+}
+
+// releaseClient returns a client for the devbox releases in namespace. The
+// typed client only creates and lists releases.
+func (s *DevboxSDK) releaseClient(namespace string) dynamic.ResourceInterface {
+	return s.dynamic.Resource(v1alpha2.GroupVersion.WithResource("devboxreleases")).Namespace(namespace)
+}
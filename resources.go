@@ -0,0 +1,237 @@
+package devbox
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gitlayzer/devbox-sdk-go/api/v1alpha2"
+	"github.com/gitlayzer/devbox-sdk-go/types"
+)
+
+// NamespaceQuota is the CPU (cores) and memory (GB) quota of a namespace.
+// Unlimited resources are reported as +Inf.
+type NamespaceQuota struct {
+	CPULimit    float64
+	MemoryLimit float64
+	CPUUsed     float64
+	MemoryUsed  float64
+}
+
+// CPUAvailable returns the CPU that can still be allocated.
+func (q *NamespaceQuota) CPUAvailable() float64 {
+	return q.CPULimit - q.CPUUsed
+}
+
+// MemoryAvailable returns the memory that can still be allocated.
+func (q *NamespaceQuota) MemoryAvailable() float64 {
+	return q.MemoryLimit - q.MemoryUsed
+}
+
+// UpdateResources sets the CPU (cores) and memory (GB) limits of the devbox.
+// Zero leaves the corresponding limit unchanged. The controller applies the
+// change to a running devbox asynchronously; use UpdateResourcesAndWait to
+// block until it has.
+func (d *Devbox) UpdateResources(ctx context.Context, cpu, memory float64) error {
+	if err := d.RefreshInfo(ctx); err != nil {
+		return err
+	}
+	return d.setResources(ctx, resourceList(cpu, memory))
+}
+
+// setResources sets the given entries of spec.resource, keeping the others,
+// with a server-side apply. Every SDK change to the resources goes through
+// it, so that they all have the same field manager.
+func (d *Devbox) setResources(ctx context.Context, changes corev1.ResourceList) error {
+	// The resources are applied on their own, so they get a manager of their
+	// own: an apply of other fields under a shared manager would remove them.
+	return d.apply(ctx, d.sdk.managerName()+"-resources", func() map[string]interface{} {
+		return map[string]interface{}{"resource": d.resourcesWith(changes)}
+	})
+}
+
+// resourcesWith returns every entry of spec.resource, with changes applied,
+// for a server-side apply. An apply removes the fields its manager applied
+// before but leaves out now, so it must carry every entry, not just the
+// changes.
+func (d *Devbox) resourcesWith(changes corev1.ResourceList) map[string]interface{} {
+	resources := map[string]interface{}{}
+	for name, quantity := range d.crd.Spec.Resource {
+		resources[string(name)] = quantity.String()
+	}
+	for name, quantity := range changes {
+		resources[string(name)] = quantity.String()
+	}
+	return resources
+}
+
+// UpdateResourcesAndWait is like UpdateResources, but if the devbox is
+// running it also waits, using the backoff settings in opts, until the devbox
+// container runs with the new limits and is ready again.
+func (d *Devbox) UpdateResourcesAndWait(ctx context.Context, cpu, memory float64, opts types.WaitForReadyOptions) error {
+	if err := d.UpdateResources(ctx, cpu, memory); err != nil {
+		return err
+	}
+	if d.crd.Spec.State != v1alpha2.DevboxStateRunning {
+		return nil
+	}
+
+	want := d.crd.Spec.Resource
+	return d.sdk.pollWithBackoff(ctx, opts, "waiting for devbox resources to be applied", func() (bool, error) {
+		pod, err := d.getPod(ctx)
+		var status apierrors.APIStatus
+		if errors.As(err, &status) {
+			return false, err
+		}
+		if err != nil {
+			// No pod while it is recreated with the new limits.
+			return false, nil
+		}
+		container := d.mainContainer(pod)
+		if container == nil {
+			return false, nil
+		}
+		for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+			w, ok := want[name]
+			if !ok {
+				continue
+			}
+			if got, ok := container.Resources.Limits[name]; !ok || got.Cmp(w) != 0 {
+				return false, nil
+			}
+		}
+		containerStatus, err := d.mainContainerStatus(pod)
+		if err != nil {
+			return false, nil
+		}
+		return containerStatus.Ready, nil
+	})
+}
+
+// ResourceRequirements contains CPU (cores) and memory (GB) requests and limits.
+type ResourceRequirements struct {
+	Requests ResourceSpec
+	Limits   ResourceSpec
+}
+
+// SetResourceRequirements sets both the requests and the limits of the devbox.
+// Requests must not exceed limits, and the limits must fit in the namespace
+// quota.
+func (d *Devbox) SetResourceRequirements(ctx context.Context, req ResourceRequirements) error {
+	if req.Requests.CPU > req.Limits.CPU {
+		return &RequestExceedsLimitError{Resource: "cpu"}
+	}
+	if req.Requests.Memory > req.Limits.Memory {
+		return &RequestExceedsLimitError{Resource: "memory"}
+	}
+
+	if err := d.RefreshInfo(ctx); err != nil {
+		return err
+	}
+	quota, err := d.GetNamespaceQuota(ctx)
+	if err != nil {
+		return err
+	}
+	// The quota usage already includes the current limits of this devbox.
+	if req.Limits.CPU-d.CPULimit() > quota.CPUAvailable() {
+		return &LimitExceedsQuotaError{Resource: "cpu"}
+	}
+	if req.Limits.Memory-d.MemoryLimit() > quota.MemoryAvailable() {
+		return &LimitExceedsQuotaError{Resource: "memory"}
+	}
+
+	resources := resourceList(req.Limits.CPU, req.Limits.Memory)
+	requests := resourceList(req.Requests.CPU, req.Requests.Memory)
+	if cpu, ok := requests[corev1.ResourceCPU]; ok {
+		resources[corev1.ResourceRequestsCPU] = cpu
+	}
+	if mem, ok := requests[corev1.ResourceMemory]; ok {
+		resources[corev1.ResourceRequestsMemory] = mem
+	}
+	return d.setResources(ctx, resources)
+}
+
+// RequestMoreResources raises the CPU and memory limits by the given amounts,
+// returning a *QuotaExceededError if the namespace quota cannot cover them.
+func (d *Devbox) RequestMoreResources(ctx context.Context, additionalCPU float64, additionalMemGiB float64) error {
+	if err := d.RefreshInfo(ctx); err != nil {
+		return err
+	}
+	quota, err := d.GetNamespaceQuota(ctx)
+	if err != nil {
+		return err
+	}
+	if shortfall := additionalCPU - quota.CPUAvailable(); shortfall > 0 {
+		return &QuotaExceededError{Resource: "cpu", Shortfall: shortfall}
+	}
+	if shortfall := additionalMemGiB - quota.MemoryAvailable(); shortfall > 0 {
+		return &QuotaExceededError{Resource: "memory", Shortfall: shortfall}
+	}
+
+	return d.UpdateResources(ctx, d.CPULimit()+additionalCPU, d.MemoryLimit()+additionalMemGiB)
+}
+
+// GetNamespaceQuota returns the tightest CPU and memory limits across the
+// ResourceQuotas of the devbox namespace.
+func (d *Devbox) GetNamespaceQuota(ctx context.Context) (*NamespaceQuota, error) {
+	quotas, err := d.sdk.client.Clientset().CoreV1().ResourceQuotas(d.crd.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &NamespaceQuota{CPULimit: math.Inf(1), MemoryLimit: math.Inf(1)}
+	cpuAvailable, memAvailable := math.Inf(1), math.Inf(1)
+	for _, q := range quotas.Items {
+		if hard, ok := quotaValue(q.Status.Hard, corev1.ResourceLimitsCPU, corev1.ResourceCPU); ok {
+			used, _ := quotaValue(q.Status.Used, corev1.ResourceLimitsCPU, corev1.ResourceCPU)
+			if cores(hard)-cores(used) < cpuAvailable {
+				cpuAvailable = cores(hard) - cores(used)
+				result.CPULimit, result.CPUUsed = cores(hard), cores(used)
+			}
+		}
+		if hard, ok := quotaValue(q.Status.Hard, corev1.ResourceLimitsMemory, corev1.ResourceMemory); ok {
+			used, _ := quotaValue(q.Status.Used, corev1.ResourceLimitsMemory, corev1.ResourceMemory)
+			if gigabytes(hard)-gigabytes(used) < memAvailable {
+				memAvailable = gigabytes(hard) - gigabytes(used)
+				result.MemoryLimit, result.MemoryUsed = gigabytes(hard), gigabytes(used)
+			}
+		}
+	}
+	return result, nil
+}
+
+// quotaValue returns the first of names present in list.
+func quotaValue(list corev1.ResourceList, names ...corev1.ResourceName) (resource.Quantity, bool) {
+	for _, name := range names {
+		if q, ok := list[name]; ok {
+			return q, true
+		}
+	}
+	return resource.Quantity{}, false
+}
+
+func cores(q resource.Quantity) float64 {
+	return float64(q.MilliValue()) / 1000
+}
+
+func gigabytes(q resource.Quantity) float64 {
+	return float64(q.Value()) / (1024 * 1024 * 1024)
+}
+
+// resourceList builds a resource list from CPU cores and memory in GB.
+// Zero values are omitted.
+func resourceList(cpu, memory float64) corev1.ResourceList {
+	list := corev1.ResourceList{}
+	if cpu > 0 {
+		list[corev1.ResourceCPU] = *resource.NewMilliQuantity(int64(cpu*1000), resource.DecimalSI)
+	}
+	if memory > 0 {
+		list[corev1.ResourceMemory] = *resource.NewQuantity(int64(memory*1024*1024*1024), resource.BinarySI)
+	}
+	return list
+}
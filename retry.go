@@ -0,0 +1,143 @@
+package devbox
+
+import (
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures how the SDK retries failed Kubernetes requests. Zero
+// fields take the defaults noted on them.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts per request, including the
+	// first. Zero means 4.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry; it doubles with
+	// every further retry. Zero means 200ms.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts. Zero means 10s.
+	MaxBackoff time.Duration
+	// Jitter randomizes each delay by up to this fraction in either
+	// direction, so that clients failing together do not retry together.
+	// Zero means no jitter.
+	Jitter float64
+	// Retryable decides whether a request is retried after it failed with
+	// err or returned resp. Nil means DefaultRetryable.
+	Retryable func(req *http.Request, resp *http.Response, err error) bool
+}
+
+// DefaultRetryable retries requests that the API server rejected without
+// processing: 429 Too Many Requests and 503 Service Unavailable, as well as
+// network errors on read-only requests. Network errors on writes are not
+// retried, as the server may have applied the write before the connection
+// failed.
+//
+// Conflicts are not retried either: resending a request with a stale
+// resourceVersion fails the same way; re-read the devbox and retry the change
+// with retry.RetryOnConflict instead.
+func DefaultRetryable(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		var netErr net.Error
+		if !errors.As(err, &netErr) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return false
+		}
+		switch req.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			return true
+		}
+		return false
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	}
+	return false
+}
+
+// WithRetryPolicy retries failed Kubernetes requests of the SDK, including
+// list and watch calls, according to policy. A Retry-After header on the
+// response overrides the computed delay.
+func WithRetryPolicy(policy RetryPolicy) DevboxSDKOption {
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = 4
+	}
+	if policy.InitialBackoff <= 0 {
+		policy.InitialBackoff = 200 * time.Millisecond
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = 10 * time.Second
+	}
+	if policy.Retryable == nil {
+		policy.Retryable = DefaultRetryable
+	}
+	return func(s *DevboxSDK) {
+		s.wrapTransport(func(rt http.RoundTripper) http.RoundTripper {
+			return &retryTransport{policy: policy, next: rt}
+		})
+	}
+}
+
+// retryTransport retries requests according to a RetryPolicy with defaults
+// filled in.
+type retryTransport struct {
+	policy RetryPolicy
+	next   http.RoundTripper
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		r := req
+		if attempt > 1 && req.Body != nil && req.Body != http.NoBody {
+			// The previous attempt consumed the body.
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r = req.Clone(req.Context())
+			r.Body = body
+		}
+
+		resp, err := t.next.RoundTrip(r)
+		if attempt >= t.policy.MaxAttempts || !t.policy.Retryable(r, resp, err) {
+			return resp, err
+		}
+		if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+			// The body cannot be replayed.
+			return resp, err
+		}
+
+		delay := t.delay(attempt, resp)
+		if resp != nil {
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// delay returns how long to wait after the given failed attempt.
+func (t *retryTransport) delay(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	delay := t.policy.InitialBackoff << (attempt - 1)
+	if delay <= 0 || delay > t.policy.MaxBackoff {
+		delay = t.policy.MaxBackoff
+	}
+	if t.policy.Jitter > 0 {
+		delay += time.Duration((rand.Float64()*2 - 1) * t.policy.Jitter * float64(delay))
+	}
+	return delay
+}
@@ -0,0 +1,46 @@
+package devbox
+
+import (
+	"context"
+)
+
+// GetRunCommand returns the command line the devbox container runs, resolved
+// the way Kubernetes does: spec.config command and args override the image
+// entrypoint and cmd. The image config is only fetched from the registry when
+// the spec does not set a command.
+func (d *Devbox) GetRunCommand(ctx context.Context) ([]string, error) {
+	if err := d.RefreshInfo(ctx); err != nil {
+		return nil, err
+	}
+	command, args := d.crd.Spec.Config.Command, d.crd.Spec.Config.Args
+	if len(command) > 0 {
+		return append(append([]string{}, command...), args...), nil
+	}
+
+	img, err := remoteImage(ctx, d.Image())
+	if err != nil {
+		return nil, err
+	}
+	configFile, err := img.ConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	if args == nil {
+		args = configFile.Config.Cmd
+	}
+	return append(append([]string{}, configFile.Config.Entrypoint...), args...), nil
+}
+
+// SetRunCommand overrides the entrypoint and arguments of the devbox
+// container. A nil command or args falls back to the image's entrypoint or
+// cmd. The devbox must be restarted for the change to take effect.
+func (d *Devbox) SetRunCommand(ctx context.Context, command, args []string) error {
+	config := map[string]interface{}{"command": nil, "args": nil}
+	if len(command) > 0 {
+		config["command"] = command
+	}
+	if args != nil {
+		config["args"] = args
+	}
+	return d.patchConfig(ctx, config)
+}
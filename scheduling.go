@@ -0,0 +1,43 @@
+package devbox
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SetPriorityClassOptions controls how the priority class is changed.
+type SetPriorityClassOptions struct {
+	// Force stops a running devbox, applies the change and starts it again.
+	// Without it, changing a running devbox returns ErrDevboxRunning.
+	Force bool
+}
+
+// SetPriorityClass sets the PriorityClass of the devbox pod. The class must
+// exist in the cluster.
+func (d *Devbox) SetPriorityClass(ctx context.Context, priorityClassName string, opts SetPriorityClassOptions) error {
+	_, err := d.sdk.client.Clientset().SchedulingV1().PriorityClasses().Get(ctx, priorityClassName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return ErrPriorityClassNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	return d.whileStopped(ctx, opts.Force, func() error {
+		return d.patch(ctx, map[string]interface{}{
+			"spec": map[string]interface{}{
+				"priorityClassName": priorityClassName,
+			},
+		})
+	})
+}
+
+// GetPriorityClass returns the PriorityClass of the devbox pod.
+func (d *Devbox) GetPriorityClass(ctx context.Context) (string, error) {
+	if err := d.RefreshInfo(ctx); err != nil {
+		return "", err
+	}
+	return d.crd.Spec.PriorityClassName, nil
+}
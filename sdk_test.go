@@ -0,0 +1,46 @@
+package devbox
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gitlayzer/devbox-sdk-go/api/v1alpha2"
+	"github.com/gitlayzer/devbox-sdk-go/devboxfake"
+)
+
+// newFakeSDK returns an SDK bound to devboxfake.Namespace on a fake server
+// holding devboxes. The server is closed when the test ends.
+func newFakeSDK(t *testing.T, devboxes ...*v1alpha2.Devbox) (*DevboxSDK, *devboxfake.Server) {
+	t.Helper()
+	srv := devboxfake.NewServer(devboxes...)
+	t.Cleanup(srv.Close)
+
+	c, dc, err := newClients(srv.Config(), devboxfake.Namespace)
+	if err != nil {
+		t.Fatalf("newClients: %v", err)
+	}
+	return &DevboxSDK{
+		client:     c,
+		dynamic:    dc,
+		cache:      newDevboxCache(),
+		restConfig: srv.Config(),
+		namespace:  devboxfake.Namespace,
+	}, srv
+}
+
+// fakeDevbox returns a devbox named name for newFakeSDK.
+func fakeDevbox(name string) *v1alpha2.Devbox {
+	return &v1alpha2.Devbox{ObjectMeta: metav1.ObjectMeta{Name: name}}
+}
+
+// lastSentVersion returns the resource version the last update or patch of
+// the named devbox was sent with.
+func lastSentVersion(t *testing.T, srv *devboxfake.Server, name string) string {
+	t.Helper()
+	sent := srv.ResourceVersions(devboxfake.Namespace, name)
+	if len(sent) == 0 {
+		t.Fatalf("no update or patch of %s was sent", name)
+	}
+	return sent[len(sent)-1]
+}
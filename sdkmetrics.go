@@ -0,0 +1,138 @@
+package devbox
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/gitlayzer/devbox-sdk-go/api/v1alpha2"
+)
+
+// Metrics holds the Prometheus metrics of the SDK. Create it once with
+// NewMetrics and pass it to every SDK with WithMetrics; copies of an SDK, such
+// as those returned by Namespace, share it.
+type Metrics struct {
+	creations    *prometheus.CounterVec
+	transitions  *prometheus.CounterVec
+	waits        *prometheus.HistogramVec
+	apiErrors    *prometheus.CounterVec
+	cacheLookups *prometheus.CounterVec
+}
+
+// NewMetrics creates the SDK metrics and registers them with reg:
+//
+//   - devbox_sdk_creations_total{outcome}: devboxes created
+//   - devbox_sdk_state_transitions_total{state, outcome}: state changes requested
+//   - devbox_sdk_wait_duration_seconds{outcome}: time spent in wait loops
+//   - devbox_sdk_api_errors_total{code}: failed Kubernetes requests by HTTP
+//     status, or "network" if no response was received
+//   - devbox_sdk_cache_lookups_total{result}: informer cache hits and misses
+//
+// The outcome label is "success" or "error"; wait loops also report "timeout".
+func NewMetrics(reg prometheus.Registerer) (*Metrics, error) {
+	m := &Metrics{
+		creations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "devbox_sdk_creations_total",
+			Help: "Devboxes created through the SDK.",
+		}, []string{"outcome"}),
+		transitions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "devbox_sdk_state_transitions_total",
+			Help: "Devbox state changes requested through the SDK.",
+		}, []string{"state", "outcome"}),
+		waits: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "devbox_sdk_wait_duration_seconds",
+			Help:    "Time the SDK spent waiting for devboxes and releases.",
+			Buckets: prometheus.ExponentialBuckets(0.5, 2, 12),
+		}, []string{"outcome"}),
+		apiErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "devbox_sdk_api_errors_total",
+			Help: "Failed Kubernetes API requests of the SDK.",
+		}, []string{"code"}),
+		cacheLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "devbox_sdk_cache_lookups_total",
+			Help: "Devbox lookups in the informer cache of the SDK.",
+		}, []string{"result"}),
+	}
+	for _, c := range []prometheus.Collector{m.creations, m.transitions, m.waits, m.apiErrors, m.cacheLookups} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// WithMetrics makes the SDK record its operations in m.
+func WithMetrics(m *Metrics) DevboxSDKOption {
+	return func(s *DevboxSDK) {
+		s.metrics = m
+		s.wrapTransport(func(rt http.RoundTripper) http.RoundTripper {
+			return &metricsTransport{metrics: m, next: rt}
+		})
+	}
+}
+
+// The record methods do nothing on a nil *Metrics, so that SDKs without
+// metrics need no checks.
+
+func (m *Metrics) recordCreation(err error) {
+	if m == nil {
+		return
+	}
+	m.creations.WithLabelValues(outcome(err)).Inc()
+}
+
+func (m *Metrics) recordTransition(state v1alpha2.DevboxState, err error) {
+	if m == nil {
+		return
+	}
+	m.transitions.WithLabelValues(string(state), outcome(err)).Inc()
+}
+
+func (m *Metrics) recordWait(d time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	result := outcome(err)
+	if errors.Is(err, ErrTimeout) {
+		result = "timeout"
+	}
+	m.waits.WithLabelValues(result).Observe(d.Seconds())
+}
+
+func (m *Metrics) recordCacheLookup(hit bool) {
+	if m == nil {
+		return
+	}
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.cacheLookups.WithLabelValues(result).Inc()
+}
+
+func outcome(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
+
+// metricsTransport counts failed requests.
+type metricsTransport struct {
+	metrics *Metrics
+	next    http.RoundTripper
+}
+
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	switch {
+	case err != nil:
+		t.metrics.apiErrors.WithLabelValues("network").Inc()
+	case resp.StatusCode >= http.StatusBadRequest:
+		t.metrics.apiErrors.WithLabelValues(strconv.Itoa(resp.StatusCode)).Inc()
+	}
+	return resp, err
+}
@@ -0,0 +1,97 @@
+package devbox
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// labelDevboxName is set on resources that belong to a devbox.
+	labelDevboxName = "devbox.sealos.run/devbox-name"
+
+	// orphanGracePeriod is how old a devbox Secret must be before it can count
+	// as orphaned, so that Secrets of a devbox that is being created or
+	// recreated are left alone.
+	orphanGracePeriod = 10 * time.Minute
+)
+
+// ListOrphanedSecrets returns the names of devbox Secrets whose devbox no
+// longer exists in the namespace. Secrets younger than ten minutes and
+// Secrets with owner references, which Kubernetes garbage-collects itself,
+// are never reported.
+func (s *DevboxSDK) ListOrphanedSecrets(ctx context.Context) ([]string, error) {
+	orphaned, err := s.orphanedSecrets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(orphaned))
+	for i, secret := range orphaned {
+		names[i] = secret.Name
+	}
+	return names, nil
+}
+
+// CleanupOrphanedSecrets deletes the Secrets reported by ListOrphanedSecrets
+// and returns how many were deleted. With dryRun set nothing is deleted and the
+// number of Secrets that would be deleted is returned.
+func (s *DevboxSDK) CleanupOrphanedSecrets(ctx context.Context, dryRun bool) (int, error) {
+	orphaned, err := s.orphanedSecrets(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if dryRun {
+		return len(orphaned), nil
+	}
+
+	deleted := 0
+	for _, secret := range orphaned {
+		// The UID precondition spares a Secret recreated under the same name
+		// since it was listed.
+		err := s.client.Clientset().CoreV1().Secrets(s.namespace).Delete(ctx, secret.Name, metav1.DeleteOptions{
+			Preconditions: &metav1.Preconditions{UID: &secret.UID},
+		})
+		if apierrors.IsNotFound(err) || apierrors.IsConflict(err) {
+			continue
+		}
+		if err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// orphanedSecrets returns the devbox Secrets reported by ListOrphanedSecrets.
+func (s *DevboxSDK) orphanedSecrets(ctx context.Context) ([]corev1.Secret, error) {
+	secrets, err := s.client.Clientset().CoreV1().Secrets(s.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labelDevboxName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// List the devboxes after the Secrets, so that a devbox created in
+	// between is seen.
+	devboxes, err := s.client.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]bool, len(devboxes.Items))
+	for _, d := range devboxes.Items {
+		existing[d.Name] = true
+	}
+
+	cutoff := time.Now().Add(-orphanGracePeriod)
+	var orphaned []corev1.Secret
+	for _, secret := range secrets.Items {
+		if existing[secret.Labels[labelDevboxName]] || len(secret.OwnerReferences) > 0 || secret.CreationTimestamp.After(cutoff) {
+			continue
+		}
+		orphaned = append(orphaned, secret)
+	}
+	return orphaned, nil
+}
@@ -0,0 +1,117 @@
+package devbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gitlayzer/devbox-sdk-go/api/v1alpha2"
+)
+
+// SelfHealOptions selects the healing strategies SelfHeal may apply.
+type SelfHealOptions struct {
+	// RecreateStuck deletes and recreates a devbox that has been pending for
+	// longer than StuckTimeout (default 10 minutes).
+	RecreateStuck bool
+	StuckTimeout  time.Duration
+
+	// GrowOnOOM raises the memory limit by MemoryStep GB (default 1) and
+	// restarts the devbox after its container was OOM-killed. MaxMemory caps
+	// the limit in GB; zero means no cap.
+	GrowOnOOM  bool
+	MemoryStep float64
+	MaxMemory  float64
+
+	// RestartUnreachable deletes the pod of a running devbox whose SSH server
+	// does not answer within SSHTimeout (default 10 seconds).
+	RestartUnreachable bool
+	SSHTimeout         time.Duration
+}
+
+// SelfHeal detects common failure scenarios of the named devbox and applies the
+// enabled recovery strategies. Every action is written to the audit log.
+func (s *DevboxSDK) SelfHeal(ctx context.Context, devboxName string, opts SelfHealOptions) error {
+	stuckTimeout := opts.StuckTimeout
+	if stuckTimeout == 0 {
+		stuckTimeout = 10 * time.Minute
+	}
+	memoryStep := opts.MemoryStep
+	if memoryStep == 0 {
+		memoryStep = 1
+	}
+	sshTimeout := opts.SSHTimeout
+	if sshTimeout == 0 {
+		sshTimeout = 10 * time.Second
+	}
+
+	crd, err := s.getDevbox(ctx, devboxName)
+	if err != nil {
+		return err
+	}
+	d := newDevbox(crd, s)
+
+	pod, err := d.getPod(ctx)
+	if err != nil {
+		pod = nil
+	}
+
+	if opts.RecreateStuck && d.crd.Status.Phase == v1alpha2.DevboxPhasePending {
+		since := d.crd.CreationTimestamp.Time
+		if pod != nil {
+			since = pod.CreationTimestamp.Time
+		}
+		if time.Since(since) > stuckTimeout {
+			_, err := d.recreate(ctx, nil)
+			d.audit(ctx, "SelfHeal", fmt.Sprintf("recreated %s after pending for %s", devboxName, time.Since(since).Round(time.Second)), err)
+			return err
+		}
+	}
+
+	if opts.GrowOnOOM && pod != nil && wasOOMKilled(d, pod) {
+		memory := d.MemoryLimit() + memoryStep
+		if opts.MaxMemory > 0 && memory > opts.MaxMemory {
+			memory = opts.MaxMemory
+		}
+		if memory > d.MemoryLimit() {
+			err := d.whileStopped(ctx, true, func() error {
+				return d.setResources(ctx, resourceList(0, memory))
+			})
+			d.audit(ctx, "SelfHeal", fmt.Sprintf("raised memory limit of %s to %gGB after OOM kill", devboxName, memory), err)
+			return err
+		}
+	}
+
+	if opts.RestartUnreachable && d.isReady() && pod != nil {
+		dialCtx, cancel := context.WithTimeout(ctx, sshTimeout)
+		client, dialErr := d.SSHDial(dialCtx)
+		cancel()
+		if dialErr == nil {
+			client.Close()
+			return nil
+		}
+
+		err := s.client.Clientset().CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{})
+		d.audit(ctx, "SelfHeal", fmt.Sprintf("restarted pod %s of %s, ssh unreachable: %v", pod.Name, devboxName, dialErr), err)
+		return err
+	}
+
+	return nil
+}
+
+// wasOOMKilled reports whether the devbox container last terminated due to OOM.
+func wasOOMKilled(d *Devbox, pod *corev1.Pod) bool {
+	status, err := d.mainContainerStatus(pod)
+	if err != nil {
+		return false
+	}
+	if t := status.LastTerminationState.Terminated; t != nil && t.Reason == "OOMKilled" {
+		return true
+	}
+	if t := status.State.Terminated; t != nil && t.Reason == "OOMKilled" {
+		return true
+	}
+	return false
+}
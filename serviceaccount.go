@@ -0,0 +1,30 @@
+package devbox
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SetServiceAccount changes the ServiceAccount the devbox pod runs as. The
+// ServiceAccount must exist in the devbox namespace and the devbox must be
+// stopped or paused.
+func (d *Devbox) SetServiceAccount(ctx context.Context, saName string) error {
+	_, err := d.sdk.client.Clientset().CoreV1().ServiceAccounts(d.crd.Namespace).Get(ctx, saName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	return d.whileStopped(ctx, false, func() error {
+		return d.patchConfig(ctx, map[string]interface{}{"serviceAccountName": saName})
+	})
+}
+
+// GetServiceAccount returns the ServiceAccount the devbox pod runs as. An empty
+// string means the namespace default ServiceAccount is used.
+func (d *Devbox) GetServiceAccount(ctx context.Context) (string, error) {
+	if err := d.RefreshInfo(ctx); err != nil {
+		return "", err
+	}
+	return d.crd.Spec.Config.ServiceAccountName, nil
+}
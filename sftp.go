@@ -0,0 +1,34 @@
+package devbox
+
+import (
+	"context"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+// sftpSession is an SFTP client together with the SSH connection it runs on.
+type sftpSession struct {
+	*sftp.Client
+	conn *ssh.Client
+}
+
+// Close closes the SFTP client and the underlying SSH connection.
+func (s *sftpSession) Close() error {
+	s.Client.Close()
+	return s.conn.Close()
+}
+
+// sftpDial opens an SFTP session to the devbox.
+func (d *Devbox) sftpDial(ctx context.Context) (*sftpSession, error) {
+	conn, err := d.SSHDial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &sftpSession{Client: client, conn: conn}, nil
+}
@@ -0,0 +1,117 @@
+package devbox
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/crypto/ssh"
+)
+
+// defaultTerm is the TERM of shells with a TTY when ShellOptions leaves it
+// empty.
+const defaultTerm = "xterm-256color"
+
+// TermSize is the size of a terminal in character cells.
+type TermSize struct {
+	Width  int
+	Height int
+}
+
+// ShellOptions configures Devbox.Shell.
+type ShellOptions struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	// Stderr is ignored when TTY is set, as the terminal merges it into
+	// Stdout.
+	Stderr io.Writer
+
+	// TTY allocates a pseudo-terminal for the session. The caller is
+	// responsible for putting its own terminal into raw mode.
+	TTY bool
+	// TermSize is the initial size of the pseudo-terminal. Zero means 80x24.
+	TermSize TermSize
+	// Term is the TERM of the pseudo-terminal. Empty means xterm-256color.
+	Term string
+	// Resize, if set, delivers new terminal sizes, which are passed on to
+	// the session as window changes.
+	Resize <-chan TermSize
+
+	// Command runs instead of the login shell if set.
+	Command string
+}
+
+// Shell opens an interactive SSH session in the devbox and returns when it
+// ends or ctx is cancelled. If the shell exits with a non-zero status, the
+// error is an *ssh.ExitError carrying it.
+func (d *Devbox) Shell(ctx context.Context, opts ShellOptions) (err error) {
+	ctx, span := d.startSpan(ctx, "devbox.Shell", attribute.Bool("devbox.shell.tty", opts.TTY))
+	defer func() { endSpan(span, err) }()
+
+	client, err := d.SSHDial(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrSSHUnavailable, err)
+	}
+	defer client.Close()
+
+	session, err := client.NewSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	session.Stdin = opts.Stdin
+	session.Stdout = opts.Stdout
+	session.Stderr = opts.Stderr
+
+	if opts.TTY {
+		size := opts.TermSize
+		if size.Width <= 0 || size.Height <= 0 {
+			size = TermSize{Width: 80, Height: 24}
+		}
+		term := opts.Term
+		if term == "" {
+			term = defaultTerm
+		}
+		modes := ssh.TerminalModes{
+			ssh.ECHO:          1,
+			ssh.TTY_OP_ISPEED: 14400,
+			ssh.TTY_OP_OSPEED: 14400,
+		}
+		if err := session.RequestPty(term, size.Height, size.Width, modes); err != nil {
+			return err
+		}
+	}
+
+	if opts.Command != "" {
+		err = session.Start(opts.Command)
+	} else {
+		err = session.Shell()
+	}
+	if err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- session.Wait() }()
+
+	for {
+		select {
+		case <-ctx.Done():
+			session.Close()
+			return ctx.Err()
+		case err := <-done:
+			return err
+		case size, ok := <-opts.Resize:
+			if !ok {
+				// Stop selecting on the closed channel.
+				opts.Resize = nil
+				continue
+			}
+			if opts.TTY {
+				session.WindowChange(size.Height, size.Width)
+			}
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package devbox
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ResourceSpec describes CPU (in cores) and memory (in GB) for a container.
+type ResourceSpec struct {
+	CPU    float64
+	Memory float64
+}
+
+// VolumeMount describes a volume mounted into a container.
+type VolumeMount struct {
+	Name      string
+	MountPath string
+	ReadOnly  bool
+}
+
+// SidecarSpec describes an additional container running next to the devbox.
+type SidecarSpec struct {
+	Name         string
+	Image        string
+	Command      []string
+	Env          map[string]string
+	Resources    ResourceSpec
+	VolumeMounts []VolumeMount
+}
+
+// InjectSidecar adds a sidecar container to the devbox.
+func (d *Devbox) InjectSidecar(ctx context.Context, sidecar SidecarSpec) error {
+	if err := d.RefreshInfo(ctx); err != nil {
+		return err
+	}
+
+	sidecars := d.crd.Spec.Config.Sidecars
+	for _, c := range sidecars {
+		if c.Name == sidecar.Name {
+			return fmt.Errorf("sidecar %q already exists", sidecar.Name)
+		}
+	}
+	sidecars = append(append([]corev1.Container{}, sidecars...), sidecar.container())
+
+	return d.patchConfig(ctx, map[string]interface{}{"sidecars": sidecars})
+}
+
+// RemoveSidecar removes the named sidecar container from the devbox.
+func (d *Devbox) RemoveSidecar(ctx context.Context, name string) error {
+	if err := d.RefreshInfo(ctx); err != nil {
+		return err
+	}
+
+	sidecars := make([]corev1.Container, 0, len(d.crd.Spec.Config.Sidecars))
+	for _, c := range d.crd.Spec.Config.Sidecars {
+		if c.Name != name {
+			sidecars = append(sidecars, c)
+		}
+	}
+	if len(sidecars) == len(d.crd.Spec.Config.Sidecars) {
+		return fmt.Errorf("sidecar %q not found", name)
+	}
+
+	return d.patchConfig(ctx, map[string]interface{}{"sidecars": sidecars})
+}
+
+// ListSidecars returns the sidecar containers configured on the devbox.
+func (d *Devbox) ListSidecars(ctx context.Context) ([]SidecarSpec, error) {
+	if err := d.RefreshInfo(ctx); err != nil {
+		return nil, err
+	}
+
+	sidecars := make([]SidecarSpec, len(d.crd.Spec.Config.Sidecars))
+	for i, c := range d.crd.Spec.Config.Sidecars {
+		sidecars[i] = sidecarFromContainer(c)
+	}
+	return sidecars, nil
+}
+
+// container converts the spec into a Kubernetes container.
+func (s SidecarSpec) container() corev1.Container {
+	c := corev1.Container{
+		Name:    s.Name,
+		Image:   s.Image,
+		Command: s.Command,
+		Env:     envVars(s.Env),
+		Resources: corev1.ResourceRequirements{
+			Limits: resourceList(s.Resources.CPU, s.Resources.Memory),
+		},
+	}
+
+	for _, m := range s.VolumeMounts {
+		c.VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{
+			Name:      m.Name,
+			MountPath: m.MountPath,
+			ReadOnly:  m.ReadOnly,
+		})
+	}
+	return c
+}
+
+// sidecarFromContainer converts a Kubernetes container back into a SidecarSpec.
+func sidecarFromContainer(c corev1.Container) SidecarSpec {
+	s := SidecarSpec{
+		Name:    c.Name,
+		Image:   c.Image,
+		Command: c.Command,
+	}
+	if cpu, ok := c.Resources.Limits[corev1.ResourceCPU]; ok {
+		s.Resources.CPU = float64(cpu.MilliValue()) / 1000
+	}
+	if mem, ok := c.Resources.Limits[corev1.ResourceMemory]; ok {
+		s.Resources.Memory = float64(mem.Value()) / (1024 * 1024 * 1024)
+	}
+	if len(c.Env) > 0 {
+		s.Env = make(map[string]string, len(c.Env))
+		for _, e := range c.Env {
+			s.Env[e.Name] = e.Value
+		}
+	}
+	for _, m := range c.VolumeMounts {
+		s.VolumeMounts = append(s.VolumeMounts, VolumeMount{
+			Name:      m.Name,
+			MountPath: m.MountPath,
+			ReadOnly:  m.ReadOnly,
+		})
+	}
+	return s
+}
@@ -0,0 +1,230 @@
+package devbox
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
+	snapshotclient "github.com/kubernetes-csi/external-snapshotter/client/v6/clientset/versioned"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	annotationDefaultSnapshotClass = "snapshot.storage.kubernetes.io/is-default-class"
+
+	// snapshotCleanupTimeout bounds the deletion of the snapshots of a
+	// failed snapshotVolumes.
+	snapshotCleanupTimeout = 30 * time.Second
+)
+
+// VolumeSnapshot describes a snapshot of one of the devbox volumes.
+type VolumeSnapshot struct {
+	Name        string
+	PVCName     string
+	CreatedAt   time.Time
+	ReadyToUse  bool
+	RestoreSize int64
+}
+
+// CreateSnapshot creates a VolumeSnapshot of every PVC of the devbox and
+// returns them, in the order of the PVCs. If one cannot be created, those
+// already created are deleted again.
+func (d *Devbox) CreateSnapshot(ctx context.Context) ([]VolumeSnapshot, error) {
+	snapshots, _, err := d.snapshotVolumes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+// snapshotVolumes creates a VolumeSnapshot of every PVC of the devbox and
+// returns the snapshots along with the PVCs they were taken from. The
+// snapshots are named after their PVC, the time and a random suffix, so that
+// snapshots taken within the same second do not collide. On failure, the
+// snapshots already created are deleted.
+func (d *Devbox) snapshotVolumes(ctx context.Context) ([]VolumeSnapshot, []corev1.PersistentVolumeClaim, error) {
+	snapshots, err := d.snapshotClient()
+	if err != nil {
+		return nil, nil, err
+	}
+	class, err := d.snapshotClassName(ctx, snapshots)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pvcs, err := d.sdk.client.Clientset().CoreV1().PersistentVolumeClaims(d.crd.Namespace).List(ctx, d.devboxSelector())
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(pvcs.Items) == 0 {
+		return nil, nil, errors.New("devbox " + d.crd.Name + " has no persistent volumes")
+	}
+
+	client := snapshots.SnapshotV1().VolumeSnapshots(d.crd.Namespace)
+	suffix := strconv.FormatInt(time.Now().Unix(), 10)
+	result := make([]VolumeSnapshot, 0, len(pvcs.Items))
+	for _, pvc := range pvcs.Items {
+		pvcName := pvc.Name
+		snapshot := &snapshotv1.VolumeSnapshot{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: pvcName + "-" + suffix + "-",
+				Namespace:    d.crd.Namespace,
+				Labels:       map[string]string{labelDevboxName: d.crd.Name},
+			},
+			Spec: snapshotv1.VolumeSnapshotSpec{
+				Source:                  snapshotv1.VolumeSnapshotSource{PersistentVolumeClaimName: &pvcName},
+				VolumeSnapshotClassName: &class,
+			},
+		}
+		created, err := client.Create(ctx, snapshot, metav1.CreateOptions{})
+		if err != nil {
+			// A partial set of snapshots is no consistent copy of the
+			// devbox, so do not leave one behind, even if ctx is done.
+			cleanupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), snapshotCleanupTimeout)
+			for _, s := range result {
+				client.Delete(cleanupCtx, s.Name, metav1.DeleteOptions{})
+			}
+			cancel()
+			return nil, nil, err
+		}
+		result = append(result, *volumeSnapshot(created))
+	}
+	return result, pvcs.Items, nil
+}
+
+// ListSnapshots returns the VolumeSnapshots taken of the devbox volumes.
+func (d *Devbox) ListSnapshots(ctx context.Context) ([]VolumeSnapshot, error) {
+	snapshots, err := d.snapshotClient()
+	if err != nil {
+		return nil, err
+	}
+	list, err := snapshots.SnapshotV1().VolumeSnapshots(d.crd.Namespace).List(ctx, d.devboxSelector())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]VolumeSnapshot, len(list.Items))
+	for i := range list.Items {
+		result[i] = *volumeSnapshot(&list.Items[i])
+	}
+	return result, nil
+}
+
+// RestoreFromSnapshot creates a new PVC from the named snapshot and switches
+// the devbox volume that used the snapshotted PVC over to it. The devbox must
+// be stopped or paused.
+func (d *Devbox) RestoreFromSnapshot(ctx context.Context, snapshotName string) error {
+	snapshots, err := d.snapshotClient()
+	if err != nil {
+		return err
+	}
+	snapshot, err := snapshots.SnapshotV1().VolumeSnapshots(d.crd.Namespace).Get(ctx, snapshotName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if snapshot.Spec.Source.PersistentVolumeClaimName == nil {
+		return errors.New("snapshot " + snapshotName + " was not taken from a PVC")
+	}
+
+	pvcs := d.sdk.client.Clientset().CoreV1().PersistentVolumeClaims(d.crd.Namespace)
+	source, err := pvcs.Get(ctx, *snapshot.Spec.Source.PersistentVolumeClaimName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	return d.whileStopped(ctx, false, func() error {
+		name := source.Name + "-restore-" + strconv.FormatInt(time.Now().Unix(), 10)
+		restored := d.restoredPVC(name, source, snapshot)
+		if _, err := pvcs.Create(ctx, restored, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+
+		volumes := make([]corev1.Volume, len(d.crd.Spec.Config.Volumes))
+		for i, v := range d.crd.Spec.Config.Volumes {
+			volumes[i] = *v.DeepCopy()
+			if pvc := volumes[i].PersistentVolumeClaim; pvc != nil && pvc.ClaimName == source.Name {
+				pvc.ClaimName = restored.Name
+			}
+		}
+		return d.patchConfig(ctx, map[string]interface{}{"volumes": volumes})
+	})
+}
+
+// restoredPVC returns a PVC named name, populated from snapshot and otherwise
+// shaped like source, the PVC the snapshot was taken from.
+func (d *Devbox) restoredPVC(name string, source *corev1.PersistentVolumeClaim, snapshot *snapshotv1.VolumeSnapshot) *corev1.PersistentVolumeClaim {
+	apiGroup := snapshotv1.GroupName
+	restored := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: d.crd.Namespace,
+			Labels:    map[string]string{labelDevboxName: d.crd.Name},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      source.Spec.AccessModes,
+			StorageClassName: source.Spec.StorageClassName,
+			Resources:        source.Spec.Resources,
+			DataSource: &corev1.TypedLocalObjectReference{
+				APIGroup: &apiGroup,
+				Kind:     "VolumeSnapshot",
+				Name:     snapshot.Name,
+			},
+		},
+	}
+	if snapshot.Status != nil && snapshot.Status.RestoreSize != nil {
+		restored.Spec.Resources.Requests = corev1.ResourceList{corev1.ResourceStorage: *snapshot.Status.RestoreSize}
+	}
+	return restored
+}
+
+// snapshotClassName picks the default VolumeSnapshotClass, or the first one
+// if none is marked as default.
+func (d *Devbox) snapshotClassName(ctx context.Context, snapshots snapshotclient.Interface) (string, error) {
+	classes, err := snapshots.SnapshotV1().VolumeSnapshotClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+	if len(classes.Items) == 0 {
+		return "", ErrVolumeSnapshotClassNotFound
+	}
+	for _, class := range classes.Items {
+		if class.Annotations[annotationDefaultSnapshotClass] == "true" {
+			return class.Name, nil
+		}
+	}
+	return classes.Items[0].Name, nil
+}
+
+func (d *Devbox) snapshotClient() (snapshotclient.Interface, error) {
+	return snapshotclient.NewForConfig(d.sdk.restConfig)
+}
+
+// devboxSelector selects objects labeled as belonging to the devbox.
+func (d *Devbox) devboxSelector() metav1.ListOptions {
+	return metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{labelDevboxName: d.crd.Name}).String(),
+	}
+}
+
+func volumeSnapshot(s *snapshotv1.VolumeSnapshot) *VolumeSnapshot {
+	result := &VolumeSnapshot{
+		Name:      s.Name,
+		CreatedAt: s.CreationTimestamp.Time,
+	}
+	if s.Spec.Source.PersistentVolumeClaimName != nil {
+		result.PVCName = *s.Spec.Source.PersistentVolumeClaimName
+	}
+	if s.Status != nil {
+		if s.Status.ReadyToUse != nil {
+			result.ReadyToUse = *s.Status.ReadyToUse
+		}
+		if s.Status.RestoreSize != nil {
+			result.RestoreSize = s.Status.RestoreSize.Value()
+		}
+	}
+	return result
+}
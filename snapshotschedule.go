@@ -0,0 +1,235 @@
+package devbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// snapshotJobImage runs the snapshot script of a SnapshotSchedule.
+	snapshotJobImage = "bitnami/kubectl:1.27"
+
+	// labelSnapshotSchedule marks the snapshots taken by a SnapshotSchedule
+	// with its name, so that retention never touches other snapshots.
+	labelSnapshotSchedule = "devbox.sealos.run/snapshot-schedule"
+)
+
+// snapshotScript snapshots every PVC of a devbox and, for each PVC, deletes
+// the oldest snapshots taken by the schedule beyond the retention limit. It
+// is formatted with the devbox label key, the devbox name, the snapshot
+// class, the number of snapshots to keep per PVC, the schedule label key and
+// the schedule name.
+const snapshotScript = `set -e
+ts=$(date +%%s)
+for pvc in $(kubectl get pvc -l %[1]s=%[2]s -o name); do
+  pvc=${pvc#*/}
+  kubectl apply -f - <<EOF
+apiVersion: snapshot.storage.k8s.io/v1
+kind: VolumeSnapshot
+metadata:
+  name: $pvc-$ts
+  labels:
+    %[1]s: %[2]s
+    %[5]s: %[6]s
+spec:
+  volumeSnapshotClassName: %[3]s
+  source:
+    persistentVolumeClaimName: $pvc
+EOF
+  kubectl get volumesnapshot -l %[5]s=%[6]s --sort-by=.metadata.creationTimestamp --no-headers \
+    -o custom-columns=NAME:.metadata.name,PVC:.spec.source.persistentVolumeClaimName |
+    awk -v pvc="$pvc" '$2 == pvc { print $1 }' | head -n -%[4]d | xargs -r kubectl delete volumesnapshot
+done
+`
+
+// SnapshotSchedule is a CronJob that periodically snapshots the volumes of a
+// devbox, keeping a bounded number of snapshots.
+type SnapshotSchedule struct {
+	Name          string
+	NextRun       time.Time
+	SnapshotCount int
+
+	devbox *Devbox
+}
+
+// CreatePeriodicSnapshot schedules a snapshot of every devbox volume each
+// interval, keeping at most maxSnapshots of the snapshots it takes per
+// volume by deleting the oldest; other snapshots of the devbox are left
+// alone. The interval must be 1, 2, 3, 4, 5, 6, 10, 12, 15, 20 or 30
+// minutes, 1, 2, 3, 4, 6, 8, 12 or 24 hours, or one week, so that cron can
+// space the runs evenly; runs are aligned to UTC midnight. The CronJob and
+// its RBAC objects are owned by the devbox and deleted with it.
+func (d *Devbox) CreatePeriodicSnapshot(ctx context.Context, interval time.Duration, maxSnapshots int) (*SnapshotSchedule, error) {
+	schedule, err := cronSchedule(interval)
+	if err != nil {
+		return nil, err
+	}
+	if maxSnapshots <= 0 {
+		return nil, fmt.Errorf("max snapshots must be positive, got %d", maxSnapshots)
+	}
+
+	snapshots, err := d.snapshotClient()
+	if err != nil {
+		return nil, err
+	}
+	class, err := d.snapshotClassName(ctx, snapshots)
+	if err != nil {
+		return nil, err
+	}
+
+	name := d.crd.Name + "-snapshot"
+	if err := d.ensureSnapshotRBAC(ctx, name); err != nil {
+		return nil, err
+	}
+
+	script := fmt.Sprintf(snapshotScript, labelDevboxName, d.crd.Name, class, maxSnapshots, labelSnapshotSchedule, name)
+	timeZone := "Etc/UTC"
+	cronJob := &batchv1.CronJob{
+		ObjectMeta: d.ownedMeta(name),
+		Spec: batchv1.CronJobSpec{
+			Schedule:          schedule,
+			TimeZone:          &timeZone,
+			ConcurrencyPolicy: batchv1.ForbidConcurrent,
+			JobTemplate: batchv1.JobTemplateSpec{
+				Spec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							ServiceAccountName: name,
+							RestartPolicy:      corev1.RestartPolicyOnFailure,
+							Containers: []corev1.Container{{
+								Name:    "snapshot",
+								Image:   snapshotJobImage,
+								Command: []string{"/bin/sh", "-c", script},
+							}},
+						},
+					},
+				},
+			},
+		},
+	}
+	if _, err := d.sdk.client.Clientset().BatchV1().CronJobs(d.crd.Namespace).Create(ctx, cronJob, metav1.CreateOptions{}); err != nil {
+		return nil, err
+	}
+
+	existing, err := d.ListSnapshots(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &SnapshotSchedule{
+		Name:          name,
+		NextRun:       time.Now().UTC().Truncate(interval).Add(interval),
+		SnapshotCount: len(existing),
+		devbox:        d,
+	}, nil
+}
+
+// Delete removes the CronJob and RBAC objects of the schedule. Snapshots
+// already taken are kept.
+func (s *SnapshotSchedule) Delete(ctx context.Context) error {
+	clientset := s.devbox.sdk.client.Clientset()
+	namespace := s.devbox.crd.Namespace
+	propagation := metav1.DeletePropagationBackground
+	opts := metav1.DeleteOptions{PropagationPolicy: &propagation}
+
+	deletes := []func() error{
+		func() error { return clientset.BatchV1().CronJobs(namespace).Delete(ctx, s.Name, opts) },
+		func() error { return clientset.RbacV1().RoleBindings(namespace).Delete(ctx, s.Name, opts) },
+		func() error { return clientset.RbacV1().Roles(namespace).Delete(ctx, s.Name, opts) },
+		func() error { return clientset.CoreV1().ServiceAccounts(namespace).Delete(ctx, s.Name, opts) },
+	}
+	for _, del := range deletes {
+		if err := del(); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// ensureSnapshotRBAC creates the service account the snapshot job runs as,
+// allowed to read PVCs and manage VolumeSnapshots in the namespace.
+func (d *Devbox) ensureSnapshotRBAC(ctx context.Context, name string) error {
+	clientset := d.sdk.client.Clientset()
+	namespace := d.crd.Namespace
+
+	_, err := clientset.CoreV1().ServiceAccounts(namespace).Create(ctx, &corev1.ServiceAccount{
+		ObjectMeta: d.ownedMeta(name),
+	}, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	_, err = clientset.RbacV1().Roles(namespace).Create(ctx, &rbacv1.Role{
+		ObjectMeta: d.ownedMeta(name),
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{""},
+				Resources: []string{"persistentvolumeclaims"},
+				Verbs:     []string{"get", "list"},
+			},
+			{
+				APIGroups: []string{"snapshot.storage.k8s.io"},
+				Resources: []string{"volumesnapshots"},
+				Verbs:     []string{"get", "list", "create", "patch", "delete"},
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	_, err = clientset.RbacV1().RoleBindings(namespace).Create(ctx, &rbacv1.RoleBinding{
+		ObjectMeta: d.ownedMeta(name),
+		Subjects: []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      name,
+			Namespace: namespace,
+		}},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     name,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// ownedMeta returns object metadata for a resource owned by the devbox.
+func (d *Devbox) ownedMeta(name string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:            name,
+		Namespace:       d.crd.Namespace,
+		Labels:          map[string]string{labelDevboxName: d.crd.Name},
+		OwnerReferences: []metav1.OwnerReference{d.ownerReference()},
+	}
+}
+
+// cronSchedule converts interval into a cron schedule. Cron steps restart at
+// every hour and every day, so only intervals that evenly divide an hour, in
+// whole minutes, or a day, in whole hours, keep runs evenly spaced. Weekly
+// intervals are supported too.
+func cronSchedule(interval time.Duration) (string, error) {
+	const day = 24 * time.Hour
+	switch {
+	case interval == 7*day:
+		// Truncating to a week aligns with Mondays.
+		return "0 0 * * 1", nil
+	case interval == day:
+		return "0 0 * * *", nil
+	case interval > 0 && interval < time.Hour && interval%time.Minute == 0 && time.Hour%interval == 0:
+		return fmt.Sprintf("*/%d * * * *", interval/time.Minute), nil
+	case interval >= time.Hour && interval%time.Hour == 0 && day%interval == 0:
+		return fmt.Sprintf("0 */%d * * *", interval/time.Hour), nil
+	default:
+		return "", fmt.Errorf("unsupported snapshot interval %s", interval)
+	}
+}
@@ -0,0 +1,393 @@
+package devbox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gitlayzer/devbox-sdk-go/api/v1alpha2"
+)
+
+const (
+	sshGateHost = "bja.sealos.run"
+	sshGatePort = 2233
+	sshPort     = 22
+
+	annotationSSHJumpHost      = "devbox.sealos.run/ssh-jump-host"
+	annotationSSHJumpUser      = "devbox.sealos.run/ssh-jump-user"
+	annotationSSHJumpKeySecret = "devbox.sealos.run/ssh-jump-key-secret"
+	// annotationSSHJumpHostKey pins the public host key of the jump host, in
+	// authorized_keys format.
+	annotationSSHJumpHostKey = "devbox.sealos.run/ssh-jump-host-key"
+)
+
+// WithSSHHostKeyCallback makes SSH connections to devboxes, and to jump hosts
+// without a pinned host key, verify the server's host key with callback. By
+// default host keys are checked against ~/.ssh/known_hosts.
+func WithSSHHostKeyCallback(callback ssh.HostKeyCallback) DevboxSDKOption {
+	return func(s *DevboxSDK) { s.sshHostKeyCallback = callback }
+}
+
+// WithInsecureSSHHostKeys disables host key verification of SSH connections
+// to devboxes and jump hosts without a pinned host key. Devbox host keys are
+// generated per pod, so this is only meant for environments where the network
+// path to the devbox is trusted.
+func WithInsecureSSHHostKeys() DevboxSDKOption {
+	return WithSSHHostKeyCallback(ssh.InsecureIgnoreHostKey())
+}
+
+// hostKeyCallback returns the callback set with WithSSHHostKeyCallback, or one
+// checking ~/.ssh/known_hosts.
+func (s *DevboxSDK) hostKeyCallback() (ssh.HostKeyCallback, error) {
+	if s.sshHostKeyCallback != nil {
+		return s.sshHostKeyCallback, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	callback, err := knownhosts.New(filepath.Join(home, ".ssh", "known_hosts"))
+	if err != nil {
+		return nil, fmt.Errorf("load known hosts: %w", err)
+	}
+	return callback, nil
+}
+
+// SSHJumpConfig describes the bastion host SSH traffic is routed through.
+type SSHJumpConfig struct {
+	Host         string
+	User         string
+	IdentityFile string
+	// HostKey is the pinned public host key of the jump host, or nil if its
+	// key is verified like that of the devbox.
+	HostKey ssh.PublicKey
+
+	privateKey []byte
+}
+
+// GetSSHJumpConfig returns the jump host configuration from the devbox
+// annotations, or nil if no jump host is configured. The private key from the
+// referenced Secret is written to a new temporary file with 0600 permissions,
+// whose path is IdentityFile. The caller is responsible for removing it.
+func (d *Devbox) GetSSHJumpConfig(ctx context.Context) (*SSHJumpConfig, error) {
+	cfg, err := d.sshJumpConfig(ctx)
+	if err != nil || cfg == nil {
+		return cfg, err
+	}
+
+	// CreateTemp opens the file with 0600 permissions under a random name, so
+	// that concurrent callers and other local users cannot race on the path.
+	f, err := os.CreateTemp("", "devbox-"+d.crd.Name+"-jump-key-")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Write(cfg.privateKey); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return nil, err
+	}
+	cfg.IdentityFile = f.Name()
+	return cfg, nil
+}
+
+// sshJumpConfig reads the jump host configuration without touching the filesystem.
+func (d *Devbox) sshJumpConfig(ctx context.Context) (*SSHJumpConfig, error) {
+	annotations := d.crd.Annotations
+	host := annotations[annotationSSHJumpHost]
+	if host == "" {
+		return nil, nil
+	}
+
+	secretName := annotations[annotationSSHJumpKeySecret]
+	if secretName == "" {
+		return nil, fmt.Errorf("annotation %s is required when %s is set", annotationSSHJumpKeySecret, annotationSSHJumpHost)
+	}
+	secret, err := d.sdk.client.Clientset().CoreV1().Secrets(d.crd.Namespace).Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	key, ok := secret.Data[corev1.SSHAuthPrivateKey]
+	if !ok {
+		return nil, fmt.Errorf("secret %s has no %s key", secretName, corev1.SSHAuthPrivateKey)
+	}
+
+	var hostKey ssh.PublicKey
+	if line := annotations[annotationSSHJumpHostKey]; line != "" {
+		hostKey, _, _, _, err = ssh.ParseAuthorizedKey([]byte(line))
+		if err != nil {
+			return nil, fmt.Errorf("annotation %s: %w", annotationSSHJumpHostKey, err)
+		}
+	}
+
+	return &SSHJumpConfig{
+		Host:       host,
+		User:       annotations[annotationSSHJumpUser],
+		HostKey:    hostKey,
+		privateKey: key,
+	}, nil
+}
+
+// SSHDial opens an SSH connection to the devbox using its stored key pair.
+// If a jump host is configured, the connection is tunneled through it. The
+// host key of the devbox is verified as configured with
+// WithSSHHostKeyCallback; that of the jump host against the key pinned in its
+// annotation, if any.
+func (d *Devbox) SSHDial(ctx context.Context) (_ *ssh.Client, err error) {
+	ctx, span := d.startSpan(ctx, "devbox.SSHDial")
+	defer func() { endSpan(span, err) }()
+
+	keyPair, err := d.GetSSHKeyPair(ctx)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := ssh.ParsePrivateKey([]byte(keyPair.PrivateKey))
+	if err != nil {
+		return nil, err
+	}
+
+	user, addr, err := d.sshAddress(ctx)
+	if err != nil {
+		return nil, err
+	}
+	d.noteAccess(ctx)
+
+	hostKeyCallback, err := d.sdk.hostKeyCallback()
+	if err != nil {
+		return nil, err
+	}
+	config := &ssh.ClientConfig{
+		User:            user,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: hostKeyCallback,
+	}
+
+	jump, err := d.sshJumpConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if jump == nil {
+		return dialSSH(ctx, addr, config)
+	}
+
+	jumpSigner, err := ssh.ParsePrivateKey(jump.privateKey)
+	if err != nil {
+		return nil, err
+	}
+	jumpHostKeyCallback := hostKeyCallback
+	if jump.HostKey != nil {
+		jumpHostKeyCallback = ssh.FixedHostKey(jump.HostKey)
+	}
+	jumpClient, err := dialSSH(ctx, withDefaultPort(jump.Host, sshPort), &ssh.ClientConfig{
+		User:            jump.User,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(jumpSigner)},
+		HostKeyCallback: jumpHostKeyCallback,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := jumpClient.Dial("tcp", addr)
+	if err != nil {
+		jumpClient.Close()
+		return nil, err
+	}
+	c, chans, reqs, err := ssh.NewClientConn(conn, addr, config)
+	if err != nil {
+		conn.Close()
+		jumpClient.Close()
+		return nil, err
+	}
+	client := ssh.NewClient(c, chans, reqs)
+	go func() {
+		client.Wait()
+		jumpClient.Close()
+	}()
+	return client, nil
+}
+
+// SSHEndpoint describes where to connect to reach the devbox over SSH.
+type SSHEndpoint struct {
+	Host string
+	Port int
+	User string
+	// ProxyJump is the jump host in the [user@]host[:port] form of ssh -J,
+	// or empty if the devbox is reached directly.
+	ProxyJump   string
+	NetworkType v1alpha2.NetworkType
+}
+
+// String formats the endpoint as an ssh command line.
+func (e SSHEndpoint) String() string {
+	var b strings.Builder
+	b.WriteString("ssh")
+	if e.ProxyJump != "" {
+		b.WriteString(" -J " + e.ProxyJump)
+	}
+	if e.Port != sshPort {
+		b.WriteString(" -p " + strconv.Itoa(e.Port))
+	}
+	b.WriteString(" ")
+	if e.User != "" {
+		b.WriteString(e.User + "@")
+	}
+	b.WriteString(e.Host)
+	return b.String()
+}
+
+// SSHEndpoint returns the SSH endpoint of the devbox. For NodePort networking
+// it looks up the address of the node running the devbox.
+func (d *Devbox) SSHEndpoint(ctx context.Context) (*SSHEndpoint, error) {
+	user, addr, err := d.sshAddress(ctx)
+	if err != nil {
+		return nil, err
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	portNum, err := strconv.Atoi(port)
+	if err != nil {
+		return nil, err
+	}
+
+	var jump string
+	if jumpHost := d.crd.Annotations[annotationSSHJumpHost]; jumpHost != "" {
+		jump = jumpHost
+		if jumpUser := d.crd.Annotations[annotationSSHJumpUser]; jumpUser != "" {
+			jump = jumpUser + "@" + jumpHost
+		}
+	}
+	return &SSHEndpoint{
+		Host:        host,
+		Port:        portNum,
+		User:        user,
+		ProxyJump:   jump,
+		NetworkType: d.crd.Status.Network.Type,
+	}, nil
+}
+
+// sshAddress resolves the SSH user and host:port for the devbox.
+func (d *Devbox) sshAddress(ctx context.Context) (string, string, error) {
+	switch d.crd.Status.Network.Type {
+	case v1alpha2.NetworkTypeSSHGate:
+		return d.crd.Status.Network.UniqueID, net.JoinHostPort(sshGateHost, strconv.Itoa(sshGatePort)), nil
+	case v1alpha2.NetworkTypeNodePort:
+		node, err := d.sdk.client.Clientset().CoreV1().Nodes().Get(ctx, d.crd.Status.Node, metav1.GetOptions{})
+		if err != nil {
+			return "", "", err
+		}
+		ip := nodeAddress(node)
+		if ip == "" {
+			return "", "", fmt.Errorf("node %s has no usable address", node.Name)
+		}
+		return d.crd.Spec.Config.User, net.JoinHostPort(ip, strconv.Itoa(int(d.crd.Status.Network.NodePort))), nil
+	default:
+		return "", "", errors.New("unsupported network type: " + string(d.crd.Status.Network.Type))
+	}
+}
+
+// nodeAddress prefers the external IP of a node over its internal IP.
+func nodeAddress(node *corev1.Node) string {
+	var internal string
+	for _, addr := range node.Status.Addresses {
+		switch addr.Type {
+		case corev1.NodeExternalIP:
+			return addr.Address
+		case corev1.NodeInternalIP:
+			internal = addr.Address
+		}
+	}
+	return internal
+}
+
+// dialSSH dials addr honoring the context deadline and performs the SSH handshake.
+func dialSSH(ctx context.Context, addr string, config *ssh.ClientConfig) (*ssh.Client, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	c, chans, reqs, err := ssh.NewClientConn(conn, addr, config)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return ssh.NewClient(c, chans, reqs), nil
+}
+
+// withDefaultPort appends port to host if it does not already carry one.
+func withDefaultPort(host string, port int) string {
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		return host
+	}
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+// runSSH runs cmd in a new SSH session, feeding it stdin if non-nil, and
+// returns its output. The command's stderr is included in the returned error
+// when it fails; failing to connect yields an error matching ErrSSHUnavailable.
+func (d *Devbox) runSSH(ctx context.Context, cmd string, stdin io.Reader) ([]byte, error) {
+	var stdout bytes.Buffer
+	err := d.streamSSH(ctx, cmd, stdin, &stdout)
+	return stdout.Bytes(), err
+}
+
+// sshDial connects runSSH and streamSSH to a devbox. Tests replace it to
+// serve sessions from a local fake.
+var sshDial = (*Devbox).SSHDial
+
+// streamSSH is like runSSH but writes the command's output to stdout as it
+// is produced.
+func (d *Devbox) streamSSH(ctx context.Context, cmd string, stdin io.Reader, stdout io.Writer) error {
+	client, err := sshDial(d, ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrSSHUnavailable, err)
+	}
+	defer client.Close()
+
+	session, err := client.NewSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	var stderr bytes.Buffer
+	session.Stdin = stdin
+	session.Stdout = stdout
+	session.Stderr = &stderr
+
+	done := make(chan error, 1)
+	go func() { done <- session.Run(cmd) }()
+
+	select {
+	case <-ctx.Done():
+		session.Close()
+		return ctx.Err()
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("%s: %w: %s", cmd, err, strings.TrimSpace(stderr.String()))
+		}
+		return nil
+	}
+}
+
+// shellQuote quotes s for safe use as a single POSIX shell word.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,119 @@
+package devbox
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// sshHandler runs cmd in a fake SSH session and returns its exit status.
+type sshHandler func(cmd string, stdout, stderr io.Writer) uint32
+
+// fakeSSH makes runSSH and streamSSH run every command with handle on a local
+// SSH server. It returns a function reporting the commands run so far.
+func fakeSSH(t *testing.T, handle sshHandler) func() []string {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hostKey, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverConfig := &ssh.ServerConfig{NoClientAuth: true}
+	serverConfig.AddHostKey(hostKey)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	var (
+		mu       sync.Mutex
+		commands []string
+	)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSSH(conn, serverConfig, func(cmd string, stdout, stderr io.Writer) uint32 {
+				mu.Lock()
+				commands = append(commands, cmd)
+				mu.Unlock()
+				return handle(cmd, stdout, stderr)
+			})
+		}
+	}()
+	stubSSHDial(t, func(d *Devbox, ctx context.Context) (*ssh.Client, error) {
+		return dialSSH(ctx, listener.Addr().String(), &ssh.ClientConfig{
+			HostKeyCallback: ssh.FixedHostKey(hostKey.PublicKey()),
+		})
+	})
+	return func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), commands...)
+	}
+}
+
+// stubSSHDial replaces sshDial with dial until the test ends.
+func stubSSHDial(t *testing.T, dial func(*Devbox, context.Context) (*ssh.Client, error)) {
+	saved := sshDial
+	sshDial = dial
+	t.Cleanup(func() { sshDial = saved })
+}
+
+// serveSSH serves exec requests on conn until the client disconnects.
+func serveSSH(conn net.Conn, config *ssh.ServerConfig, handle sshHandler) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+	for newChannel := range chans {
+		if newChannel.ChannelType() != "session" {
+			newChannel.Reject(ssh.UnknownChannelType, "only sessions are supported")
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer channel.Close()
+			for req := range requests {
+				if req.Type != "exec" {
+					req.Reply(false, nil)
+					continue
+				}
+				var exec struct{ Command string }
+				if err := ssh.Unmarshal(req.Payload, &exec); err != nil {
+					req.Reply(false, nil)
+					continue
+				}
+				req.Reply(true, nil)
+				status := handle(exec.Command, channel, channel.Stderr())
+				channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{status}))
+				return
+			}
+		}()
+	}
+}
+
+// unavailableSSH makes every SSH connection fail.
+func unavailableSSH(t *testing.T) {
+	stubSSHDial(t, func(*Devbox, context.Context) (*ssh.Client, error) {
+		return nil, errors.New("connection refused")
+	})
+}
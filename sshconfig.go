@@ -0,0 +1,159 @@
+package devbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/gitlayzer/devbox-sdk-go/api/v1alpha2"
+)
+
+// SSHConfigOptions configures the Host block of SSHConfigEntry.
+type SSHConfigOptions struct {
+	// IdentityFile is the path of the devbox private key.
+	IdentityFile string
+	// JumpIdentityFile is the path of the jump host private key. It is
+	// required if the devbox has a jump host.
+	JumpIdentityFile string
+	// JumpKnownHostsFile is the path of a known_hosts file holding the pinned
+	// host key of the jump host, if it has one.
+	JumpKnownHostsFile string
+	// InsecureHostKeys turns off host key checking for the devbox, and for
+	// the jump host unless its key is pinned. Devbox host keys are generated
+	// per pod, so without it ssh asks to confirm a new key after a restart.
+	InsecureHostKeys bool
+}
+
+// SSHConfigEntry returns a Host block for ~/.ssh/config that connects to the
+// devbox under its name. If a jump host is configured, the block tunnels
+// through it with a ProxyCommand, which unlike ProxyJump can use the jump
+// host's own key. For SSHGate networking the block names the host by the
+// devbox's unique ID and reaches the shared gate with a ProxyCommand, so
+// that known_hosts keeps the devboxes behind the gate apart. No files are
+// written; see WriteSSHConfig.
+func (d *Devbox) SSHConfigEntry(ctx context.Context, opts SSHConfigOptions) (string, error) {
+	user, addr, err := d.sshAddress(ctx)
+	if err != nil {
+		return "", err
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", err
+	}
+	jump, err := d.sshJumpConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Host %s\n", d.crd.Name)
+	if d.crd.Status.Network.Type == v1alpha2.NetworkTypeSSHGate {
+		fmt.Fprintf(&b, "  HostName %s\n", d.crd.Status.Network.UniqueID)
+	} else {
+		fmt.Fprintf(&b, "  HostName %s\n", host)
+	}
+	fmt.Fprintf(&b, "  Port %s\n", port)
+	fmt.Fprintf(&b, "  User %s\n", user)
+	fmt.Fprintf(&b, "  IdentityFile %s\n", opts.IdentityFile)
+	b.WriteString("  IdentitiesOnly yes\n")
+	if opts.InsecureHostKeys {
+		b.WriteString("  StrictHostKeyChecking no\n")
+		b.WriteString("  UserKnownHostsFile /dev/null\n")
+	}
+
+	switch {
+	case jump != nil:
+		if opts.JumpIdentityFile == "" {
+			return "", errors.New("JumpIdentityFile is required for a devbox with a jump host")
+		}
+		jumpHost, jumpPort, err := net.SplitHostPort(withDefaultPort(jump.Host, sshPort))
+		if err != nil {
+			return "", err
+		}
+		if jump.User != "" {
+			jumpHost = jump.User + "@" + jumpHost
+		}
+		fmt.Fprintf(&b, "  ProxyCommand ssh -i %s -o IdentitiesOnly=yes", opts.JumpIdentityFile)
+		switch {
+		case jump.HostKey != nil && opts.JumpKnownHostsFile != "":
+			fmt.Fprintf(&b, " -o StrictHostKeyChecking=yes -o UserKnownHostsFile=%s", opts.JumpKnownHostsFile)
+		case opts.InsecureHostKeys:
+			b.WriteString(" -o StrictHostKeyChecking=no -o UserKnownHostsFile=/dev/null")
+		}
+		fmt.Fprintf(&b, " -p %s -W %s %s\n", jumpPort, addr, jumpHost)
+	case d.crd.Status.Network.Type == v1alpha2.NetworkTypeSSHGate:
+		fmt.Fprintf(&b, "  ProxyCommand nc %s %s\n", host, port)
+	}
+	return b.String(), nil
+}
+
+// WriteSSHConfig adds the Host block of SSHConfigEntry to the SSH config file
+// at path, creating the file if needed, so that "ssh <devbox name>" connects
+// to the devbox. The private key is written next to the config file as
+// devbox-<name>.key with 0600 permissions, and likewise the jump host key as
+// devbox-<name>-jump.key and its pinned host key as
+// devbox-<name>-jump.known_hosts; the file paths in opts are ignored. A block
+// previously written for the same devbox is replaced.
+func (d *Devbox) WriteSSHConfig(ctx context.Context, path string, opts SSHConfigOptions) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	keyPair, err := d.GetSSHKeyPair(ctx)
+	if err != nil {
+		return err
+	}
+	opts.IdentityFile = filepath.Join(dir, "devbox-"+d.crd.Name+".key")
+	if err := os.WriteFile(opts.IdentityFile, []byte(keyPair.PrivateKey), 0o600); err != nil {
+		return err
+	}
+
+	opts.JumpIdentityFile, opts.JumpKnownHostsFile = "", ""
+	jump, err := d.sshJumpConfig(ctx)
+	if err != nil {
+		return err
+	}
+	if jump != nil {
+		opts.JumpIdentityFile = filepath.Join(dir, "devbox-"+d.crd.Name+"-jump.key")
+		if err := os.WriteFile(opts.JumpIdentityFile, jump.privateKey, 0o600); err != nil {
+			return err
+		}
+		if jump.HostKey != nil {
+			opts.JumpKnownHostsFile = filepath.Join(dir, "devbox-"+d.crd.Name+"-jump.known_hosts")
+			line := knownhosts.Line([]string{knownhosts.Normalize(withDefaultPort(jump.Host, sshPort))}, jump.HostKey)
+			if err := os.WriteFile(opts.JumpKnownHostsFile, []byte(line+"\n"), 0o600); err != nil {
+				return err
+			}
+		}
+	}
+
+	entry, err := d.SSHConfigEntry(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	existing, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	begin := "# BEGIN devbox " + d.crd.Name + "\n"
+	end := "# END devbox " + d.crd.Name + "\n"
+	config := string(existing)
+	if i := strings.Index(config, begin); i >= 0 {
+		if j := strings.Index(config[i:], end); j >= 0 {
+			config = config[:i] + config[i+j+len(end):]
+		}
+	}
+	if config != "" && !strings.HasSuffix(config, "\n") {
+		config += "\n"
+	}
+	config += begin + entry + end
+
+	return os.WriteFile(path, []byte(config), 0o600)
+}
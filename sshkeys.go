@@ -0,0 +1,163 @@
+package devbox
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// SSHCopyID adds publicKey to ~/.ssh/authorized_keys inside the devbox, like
+// ssh-copy-id. The directory and file are created with 0700 and 0600
+// permissions if missing. Adding a key that is already present is a no-op.
+func (d *Devbox) SSHCopyID(ctx context.Context, publicKey string) error {
+	key := strings.TrimSpace(publicKey)
+	if key == "" {
+		return errors.New("public key is empty")
+	}
+
+	session, err := d.sftpDial(ctx)
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	file, lines, err := readAuthorizedKeys(session)
+	if err != nil {
+		return err
+	}
+	for _, line := range lines {
+		if sameKey(line, key) {
+			return nil
+		}
+	}
+
+	return writeAuthorizedKeys(session, file, append(lines, key))
+}
+
+// SSHRemoveID removes publicKey from ~/.ssh/authorized_keys inside the devbox.
+// It refuses with ErrManagedKey to remove the devbox's own key, without which
+// the SDK could no longer connect.
+func (d *Devbox) SSHRemoveID(ctx context.Context, publicKey string) error {
+	key := strings.TrimSpace(publicKey)
+	keyPair, err := d.GetSSHKeyPair(ctx)
+	if err != nil {
+		return err
+	}
+	if sameKey(keyPair.PublicKey, key) {
+		return ErrManagedKey
+	}
+
+	session, err := d.sftpDial(ctx)
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	file, lines, err := readAuthorizedKeys(session)
+	if err != nil {
+		return err
+	}
+	kept := lines[:0]
+	for _, line := range lines {
+		if !sameKey(line, key) {
+			kept = append(kept, line)
+		}
+	}
+	if len(kept) == len(lines) {
+		return nil
+	}
+
+	return writeAuthorizedKeys(session, file, kept)
+}
+
+// readAuthorizedKeys returns the path of authorized_keys, creating ~/.ssh if
+// needed, and its non-empty lines.
+func readAuthorizedKeys(session *sftpSession) (string, []string, error) {
+	home, err := session.Getwd()
+	if err != nil {
+		return "", nil, err
+	}
+	dir := path.Join(home, ".ssh")
+	if _, err := session.Stat(dir); errors.Is(err, os.ErrNotExist) {
+		if err := session.Mkdir(dir); err != nil {
+			return "", nil, err
+		}
+		if err := session.Chmod(dir, 0700); err != nil {
+			return "", nil, err
+		}
+	} else if err != nil {
+		return "", nil, err
+	}
+
+	file := path.Join(dir, "authorized_keys")
+	f, err := session.Open(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return file, nil, nil
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return "", nil, err
+	}
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return file, lines, nil
+}
+
+// writeAuthorizedKeys replaces the contents of file with lines. The lines are
+// written to a temporary file in the same directory, which is renamed over
+// file once complete, so that a failed write never leaves a truncated
+// authorized_keys behind.
+func writeAuthorizedKeys(session *sftpSession, file string, lines []string) error {
+	tmp := file + ".devbox-sdk.tmp"
+	f, err := session.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write([]byte(strings.Join(lines, "\n") + "\n")); err != nil {
+		f.Close()
+		session.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		session.Remove(tmp)
+		return err
+	}
+	if err := session.Chmod(tmp, 0600); err != nil {
+		session.Remove(tmp)
+		return err
+	}
+	if err := session.PosixRename(tmp, file); err != nil {
+		session.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// sameKey compares two authorized_keys entries by key type and data, ignoring
+// options and comments.
+func sameKey(a, b string) bool {
+	ka, kb := keyFields(a), keyFields(b)
+	return ka != "" && ka == kb
+}
+
+func keyFields(line string) string {
+	fields := strings.Fields(line)
+	for i := 0; i+1 < len(fields); i++ {
+		if strings.HasPrefix(fields[i], "ssh-") || strings.HasPrefix(fields[i], "ecdsa-") || strings.HasPrefix(fields[i], "sk-") {
+			return fields[i] + " " + fields[i+1]
+		}
+	}
+	return ""
+}
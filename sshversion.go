@@ -0,0 +1,53 @@
+package devbox
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"strings"
+)
+
+// maxBannerLines bounds how many pre-identification lines GetSSHServerVersion
+// reads before giving up; RFC 4253 allows servers to send such lines.
+const maxBannerLines = 16
+
+// GetSSHServerVersion connects to the SSH address of the devbox and returns the
+// identification string the server sends before key exchange, such as
+// "SSH-2.0-OpenSSH_9.3". No handshake is performed and no jump host is used;
+// for SSHGate devboxes the gateway answers. The context deadline bounds the
+// whole exchange.
+func (d *Devbox) GetSSHServerVersion(ctx context.Context) (string, error) {
+	_, addr, err := d.sshAddress(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	reader := bufio.NewReaderSize(conn, 256)
+	for i := 0; i < maxBannerLines; i++ {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
+			return "", err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if strings.HasPrefix(line, "SSH-") {
+			return line, nil
+		}
+	}
+	return "", errors.New("no SSH identification string received from " + addr)
+}
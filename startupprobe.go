@@ -0,0 +1,40 @@
+package devbox
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// GetStartupProbeStatus reports whether the kubelet considers the devbox
+// container started, i.e. whether its startup probe has passed, and roughly
+// when. Kubernetes does not record the exact time the probe passed, so
+// passedAt is when the pod's containers last became ready, or the container
+// start time if they have not. Containers without a startup probe count as
+// started as soon as they run.
+func (d *Devbox) GetStartupProbeStatus(ctx context.Context) (bool, *time.Time, error) {
+	pod, err := d.getPod(ctx)
+	if err != nil {
+		return false, nil, err
+	}
+	status, err := d.mainContainerStatus(pod)
+	if err != nil {
+		return false, nil, err
+	}
+	if status.Started == nil || !*status.Started {
+		return false, nil, nil
+	}
+
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.ContainersReady && c.Status == corev1.ConditionTrue && !c.LastTransitionTime.IsZero() {
+			passedAt := c.LastTransitionTime.Time
+			return true, &passedAt, nil
+		}
+	}
+	if r := status.State.Running; r != nil {
+		passedAt := r.StartedAt.Time
+		return true, &passedAt, nil
+	}
+	return true, nil, nil
+}
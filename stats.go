@@ -0,0 +1,81 @@
+package devbox
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// InterfaceStats contains traffic counters for a single network interface.
+type InterfaceStats struct {
+	Interface string
+	RxBytes   int64
+	TxBytes   int64
+	RxPackets int64
+	TxPackets int64
+	RxErrors  int64
+	TxErrors  int64
+}
+
+// NetworkStats contains traffic counters for all interfaces in the devbox.
+// The totals exclude the loopback interface.
+type NetworkStats struct {
+	Interfaces   []InterfaceStats
+	TotalRxBytes int64
+	TotalTxBytes int64
+}
+
+// GetNetworkStats reads /proc/net/dev inside the devbox.
+func (d *Devbox) GetNetworkStats(ctx context.Context) (*NetworkStats, error) {
+	out, err := d.runSSH(ctx, "cat /proc/net/dev", nil)
+	if err != nil {
+		return nil, err
+	}
+	return parseNetDev(out)
+}
+
+// parseNetDev parses the contents of /proc/net/dev.
+func parseNetDev(data []byte) (*NetworkStats, error) {
+	stats := &NetworkStats{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		name, counters, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			// Header lines
+			continue
+		}
+		fields := strings.Fields(counters)
+		if len(fields) < 16 {
+			return nil, fmt.Errorf("unexpected /proc/net/dev line: %q", scanner.Text())
+		}
+
+		values := make([]int64, 16)
+		for i := range values {
+			v, err := strconv.ParseInt(fields[i], 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = v
+		}
+
+		iface := InterfaceStats{
+			Interface: strings.TrimSpace(name),
+			RxBytes:   values[0],
+			RxPackets: values[1],
+			RxErrors:  values[2],
+			TxBytes:   values[8],
+			TxPackets: values[9],
+			TxErrors:  values[10],
+		}
+		stats.Interfaces = append(stats.Interfaces, iface)
+		if iface.Interface != "lo" {
+			stats.TotalRxBytes += iface.RxBytes
+			stats.TotalTxBytes += iface.TxBytes
+		}
+	}
+	return stats, scanner.Err()
+}
@@ -0,0 +1,101 @@
+package devbox
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+const sampleNetDev = `Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+    lo:    2776      32    0    0    0     0          0         0     2776      32    0    0    0     0       0          0
+  eth0: 1638400   12001    3    0    0     0          0         0   409600    5002    1    0    0     0       0          0
+  eth1:     100       2    0    0    0     0          0         0      200       3    0    0    0     0       0          0
+`
+
+func TestParseNetDev(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    *NetworkStats
+		wantErr bool
+	}{
+		{
+			name: "interfaces",
+			data: sampleNetDev,
+			want: &NetworkStats{
+				Interfaces: []InterfaceStats{
+					{Interface: "lo", RxBytes: 2776, TxBytes: 2776, RxPackets: 32, TxPackets: 32},
+					{Interface: "eth0", RxBytes: 1638400, TxBytes: 409600, RxPackets: 12001, TxPackets: 5002, RxErrors: 3, TxErrors: 1},
+					{Interface: "eth1", RxBytes: 100, TxBytes: 200, RxPackets: 2, TxPackets: 3},
+				},
+				TotalRxBytes: 1638500,
+				TotalTxBytes: 409800,
+			},
+		},
+		{
+			name: "counters glued to the name",
+			data: "eth0:1638400 12001 3 0 0 0 0 0 409600 5002 1 0 0 0 0 0\n",
+			want: &NetworkStats{
+				Interfaces: []InterfaceStats{
+					{Interface: "eth0", RxBytes: 1638400, TxBytes: 409600, RxPackets: 12001, TxPackets: 5002, RxErrors: 3, TxErrors: 1},
+				},
+				TotalRxBytes: 1638400,
+				TotalTxBytes: 409600,
+			},
+		},
+		{
+			name: "headers only",
+			data: "Inter-|   Receive |  Transmit\n face |bytes packets|bytes packets\n",
+			want: &NetworkStats{},
+		},
+		{
+			name:    "too few counters",
+			data:    "  eth0: 1 2 3\n",
+			wantErr: true,
+		},
+		{
+			name:    "non-numeric counter",
+			data:    "  eth0: 1 2 3 0 0 0 0 0 x 5 1 0 0 0 0 0\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseNetDev([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseNetDev() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseNetDev() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetNetworkStats(t *testing.T) {
+	ctx := context.Background()
+	d := newDevbox(fakeDevbox("alice"), &DevboxSDK{})
+
+	commands := fakeSSH(t, func(cmd string, stdout, stderr io.Writer) uint32 {
+		io.WriteString(stdout, sampleNetDev)
+		return 0
+	})
+	stats, err := d.GetNetworkStats(ctx)
+	if err != nil {
+		t.Fatalf("GetNetworkStats: %v", err)
+	}
+	if want := []string{"cat /proc/net/dev"}; !reflect.DeepEqual(commands(), want) {
+		t.Errorf("commands = %q, want %q", commands(), want)
+	}
+	if stats.TotalRxBytes != 1638500 || stats.TotalTxBytes != 409800 {
+		t.Errorf("totals = %d/%d, want 1638500/409800", stats.TotalRxBytes, stats.TotalTxBytes)
+	}
+
+	unavailableSSH(t)
+	if _, err := d.GetNetworkStats(ctx); !errors.Is(err, ErrSSHUnavailable) {
+		t.Errorf("GetNetworkStats without SSH: error = %v, want ErrSSHUnavailable", err)
+	}
+}
@@ -0,0 +1,96 @@
+package devbox
+
+import (
+	"net"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/gitlayzer/devbox-sdk-go/api/v1alpha2"
+)
+
+// DevboxStatusSummary is a point-in-time snapshot of the cached devbox state.
+type DevboxStatusSummary struct {
+	Name          string            `json:"name"`
+	UID           string            `json:"uid"`
+	Phase         string            `json:"phase"`
+	State         string            `json:"state"`
+	Image         string            `json:"image"`
+	CPULimit      float64           `json:"cpuLimit"`
+	MemoryLimit   float64           `json:"memoryLimit"`
+	CPURequest    float64           `json:"cpuRequest"`
+	MemoryRequest float64           `json:"memoryRequest"`
+	NodeName      string            `json:"nodeName,omitempty"`
+	NetworkType   string            `json:"networkType,omitempty"`
+	SSHAddress    string            `json:"sshAddress,omitempty"`
+	CreatedAt     time.Time         `json:"createdAt"`
+	Labels        map[string]string `json:"labels,omitempty"`
+	Annotations   map[string]string `json:"annotations,omitempty"`
+}
+
+// GetStatusSummary assembles a summary from the cached CRD without calling the
+// Kubernetes API. Call RefreshInfo first if an up-to-date summary is needed.
+func (d *Devbox) GetStatusSummary() DevboxStatusSummary {
+	return DevboxStatusSummary{
+		Name:          d.Name(),
+		UID:           d.UID(),
+		Phase:         d.Status(),
+		State:         d.State(),
+		Image:         d.Image(),
+		CPULimit:      d.CPULimit(),
+		MemoryLimit:   d.MemoryLimit(),
+		CPURequest:    d.CPURequest(),
+		MemoryRequest: d.MemoryRequest(),
+		NodeName:      d.Node(),
+		NetworkType:   d.NetworkType(),
+		SSHAddress:    d.cachedSSHAddress(),
+		CreatedAt:     d.CreatedAt(),
+		Labels:        copyStringMap(d.crd.Labels),
+		Annotations:   copyStringMap(d.crd.Annotations),
+	}
+}
+
+// CPURequest returns the CPU request in cores.
+func (d *Devbox) CPURequest() float64 {
+	if cpu, ok := d.crd.Spec.Resource[corev1.ResourceRequestsCPU]; ok {
+		return float64(cpu.MilliValue()) / 1000
+	}
+	return 0
+}
+
+// MemoryRequest returns the memory request in GB.
+func (d *Devbox) MemoryRequest() float64 {
+	if mem, ok := d.crd.Spec.Resource[corev1.ResourceRequestsMemory]; ok {
+		return float64(mem.Value()) / (1024 * 1024 * 1024)
+	}
+	return 0
+}
+
+// cachedSSHAddress returns the SSH host:port known from the cached CRD. For
+// NodePort devboxes the node name is used, since resolving its IP needs an API call.
+func (d *Devbox) cachedSSHAddress() string {
+	network := d.crd.Status.Network
+	switch network.Type {
+	case v1alpha2.NetworkTypeSSHGate:
+		return net.JoinHostPort(sshGateHost, strconv.Itoa(sshGatePort))
+	case v1alpha2.NetworkTypeNodePort:
+		if d.crd.Status.Node == "" || network.NodePort == 0 {
+			return ""
+		}
+		return net.JoinHostPort(d.crd.Status.Node, strconv.Itoa(int(network.NodePort)))
+	default:
+		return ""
+	}
+}
+
+func copyStringMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,113 @@
+package devbox
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// systemInfoCommand prints the sources of SystemInfo, separated by "---"
+// lines.
+const systemInfoCommand = "uname -a; echo ---; cat /etc/os-release 2>/dev/null; echo ---; " +
+	"grep -m1 -E '^(model name|Model|Hardware)' /proc/cpuinfo; nproc; echo ---; " +
+	"df -B1 -P / | tail -n 1; echo ---; cat /proc/meminfo"
+
+// SystemInfo describes the runtime environment of the devbox. OS is the
+// PRETTY_NAME from /etc/os-release, or the kernel name if that file is
+// missing. CPUCores is the number of CPUs the devbox may run on, which can
+// exceed its CPU limit. The RAM figures come from /proc/meminfo and reflect
+// the node rather than the container's memory limit unless the runtime
+// virtualizes it; FreeRAMGiB is the available memory, including reclaimable
+// caches. The disk figures are for the root filesystem.
+type SystemInfo struct {
+	Uname         string
+	OS            string
+	KernelVersion string
+	CPUModel      string
+	CPUCores      int
+	TotalRAMGiB   float64
+	FreeRAMGiB    float64
+	TotalDiskGiB  float64
+	FreeDiskGiB   float64
+}
+
+// GetSystemInfo gathers the operating system, kernel, CPU, memory and disk
+// details of the devbox over SSH.
+func (d *Devbox) GetSystemInfo(ctx context.Context) (*SystemInfo, error) {
+	out, err := d.runSSH(ctx, systemInfoCommand, nil)
+	if err != nil {
+		return nil, err
+	}
+	return parseSystemInfo(out)
+}
+
+// parseSystemInfo parses the output of systemInfoCommand.
+func parseSystemInfo(data []byte) (*SystemInfo, error) {
+	sections := bytes.Split(data, []byte("---\n"))
+	if len(sections) != 5 {
+		return nil, fmt.Errorf("unexpected system info output: %q", data)
+	}
+	uname, osRelease, cpu, disk, meminfo := sections[0], sections[1], sections[2], sections[3], sections[4]
+
+	info := &SystemInfo{Uname: strings.TrimSpace(string(uname))}
+	unameFields := strings.Fields(info.Uname)
+	if len(unameFields) < 3 {
+		return nil, fmt.Errorf("unexpected uname output: %q", uname)
+	}
+	info.OS = unameFields[0]
+	info.KernelVersion = unameFields[2]
+
+	scanner := bufio.NewScanner(bytes.NewReader(osRelease))
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(scanner.Text(), "PRETTY_NAME="); ok {
+			info.OS = strings.Trim(value, `"'`)
+		}
+	}
+
+	cpuLines := strings.Split(strings.TrimSpace(string(cpu)), "\n")
+	cores, err := strconv.Atoi(strings.TrimSpace(cpuLines[len(cpuLines)-1]))
+	if err != nil {
+		return nil, fmt.Errorf("unexpected nproc output: %q", cpu)
+	}
+	info.CPUCores = cores
+	if len(cpuLines) > 1 {
+		if _, model, ok := strings.Cut(cpuLines[0], ":"); ok {
+			info.CPUModel = strings.TrimSpace(model)
+		}
+	}
+
+	// Filesystem 1-blocks Used Available Capacity Mounted-on
+	diskFields := strings.Fields(string(disk))
+	if len(diskFields) < 4 {
+		return nil, fmt.Errorf("unexpected df output: %q", disk)
+	}
+	total, err1 := strconv.ParseInt(diskFields[1], 10, 64)
+	free, err2 := strconv.ParseInt(diskFields[3], 10, 64)
+	if err1 != nil || err2 != nil {
+		return nil, fmt.Errorf("unexpected df output: %q", disk)
+	}
+	info.TotalDiskGiB = float64(total) / (1024 * 1024 * 1024)
+	info.FreeDiskGiB = float64(free) / (1024 * 1024 * 1024)
+
+	scanner = bufio.NewScanner(bytes.NewReader(meminfo))
+	for scanner.Scan() {
+		name, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok || (name != "MemTotal" && name != "MemAvailable") {
+			continue
+		}
+		kib, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimSpace(value), " kB"), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected /proc/meminfo line: %q", scanner.Text())
+		}
+		gib := float64(kib) / (1024 * 1024)
+		if name == "MemTotal" {
+			info.TotalRAMGiB = gib
+		} else {
+			info.FreeRAMGiB = gib
+		}
+	}
+	return info, scanner.Err()
+}
@@ -0,0 +1,68 @@
+package devbox
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// UploadTarball extracts a gzip-compressed tar stream into remoteDir on the
+// devbox, creating the directory if needed.
+func (d *Devbox) UploadTarball(ctx context.Context, r io.Reader, remoteDir string) error {
+	dir := shellQuote(remoteDir)
+	_, err := d.runSSH(ctx, "mkdir -p "+dir+" && tar -xzf - -C "+dir, r)
+	return err
+}
+
+// writeTarball writes the contents of dir to w as a gzip-compressed tar stream.
+func writeTarball(dir string, w io.Writer) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+
+		var link string
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		}
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
@@ -0,0 +1,124 @@
+package devbox
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Template is a reusable devbox configuration, such as a language runtime
+// with its usual ports and resources. Config.Name is ignored; the devbox name
+// is given when creating from the template. The templates package provides
+// common templates.
+type Template struct {
+	Name        string
+	Description string
+	Config      DevboxConfig
+}
+
+var (
+	templatesMu sync.RWMutex
+	templates   = map[string]Template{}
+)
+
+// RegisterTemplate makes t available to CreateFromTemplate under t.Name,
+// replacing any template registered under the same name.
+func RegisterTemplate(t Template) {
+	templatesMu.Lock()
+	defer templatesMu.Unlock()
+	templates[t.Name] = t
+}
+
+// LookupTemplate returns the template registered under name.
+func LookupTemplate(name string) (Template, bool) {
+	templatesMu.RLock()
+	defer templatesMu.RUnlock()
+	t, ok := templates[name]
+	return t, ok
+}
+
+// Templates returns the registered templates sorted by name.
+func Templates() []Template {
+	templatesMu.RLock()
+	defer templatesMu.RUnlock()
+	list := make([]Template, 0, len(templates))
+	for _, t := range templates {
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// CreateFromTemplate creates a devbox named name from the registered
+// template. Non-zero fields of overrides replace those of the template, and
+// its Env, Labels and Annotations are merged into the template's; its Name is
+// ignored. The template is recorded in the provenance of the devbox. An
+// unknown template yields an error matching ErrNotFound.
+func (s *DevboxSDK) CreateFromTemplate(ctx context.Context, template, name string, overrides DevboxConfig) (*Devbox, error) {
+	t, ok := LookupTemplate(template)
+	if !ok {
+		return nil, fmt.Errorf("template %q: %w", template, ErrNotFound)
+	}
+	cfg := t.Config.merge(overrides)
+	cfg.Name = name
+
+	provenance, err := s.appendProvenance(cfg.Annotations, ProvenanceEvent{
+		Action:       "CreateFromTemplate",
+		SourceDevbox: template,
+	})
+	if err != nil {
+		return nil, err
+	}
+	cfg.Annotations = mergeStringMaps(cfg.Annotations, map[string]string{annotationProvenance: provenance})
+	return s.CreateDevbox(ctx, cfg)
+}
+
+// merge returns cfg with the non-zero fields of overrides applied. Maps are
+// merged; slices are replaced.
+func (cfg DevboxConfig) merge(overrides DevboxConfig) DevboxConfig {
+	if overrides.Image != "" {
+		cfg.Image = overrides.Image
+	}
+	if overrides.CPU != 0 {
+		cfg.CPU = overrides.CPU
+	}
+	if overrides.Memory != 0 {
+		cfg.Memory = overrides.Memory
+	}
+	if overrides.Ports != nil {
+		cfg.Ports = overrides.Ports
+	}
+	if overrides.AppPorts != nil {
+		cfg.AppPorts = overrides.AppPorts
+	}
+	if overrides.WorkingDir != "" {
+		cfg.WorkingDir = overrides.WorkingDir
+	}
+	if overrides.User != "" {
+		cfg.User = overrides.User
+	}
+	if overrides.NetworkType != "" {
+		cfg.NetworkType = overrides.NetworkType
+	}
+	cfg.Env = mergeStringMaps(cfg.Env, overrides.Env)
+	cfg.Labels = mergeStringMaps(cfg.Labels, overrides.Labels)
+	cfg.Annotations = mergeStringMaps(cfg.Annotations, overrides.Annotations)
+	return cfg
+}
+
+// mergeStringMaps returns a new map with the entries of base and then those
+// of overrides, or nil if both are empty.
+func mergeStringMaps(base, overrides map[string]string) map[string]string {
+	if len(base) == 0 && len(overrides) == 0 {
+		return nil
+	}
+	merged := copyStringMap(base)
+	if merged == nil {
+		merged = make(map[string]string, len(overrides))
+	}
+	for k, v := range overrides {
+		merged[k] = v
+	}
+	return merged
+}
@@ -0,0 +1,72 @@
+// Package templates defines devbox templates for common language runtimes.
+// Importing the package registers them with the SDK, so that they can be
+// used by name:
+//
+//	import _ "github.com/gitlayzer/devbox-sdk-go/templates"
+//
+//	d, err := sdk.CreateFromTemplate(ctx, "go-1.22", "alice", devbox.DevboxConfig{})
+//
+// Register organization-specific templates with devbox.RegisterTemplate.
+package templates
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	devbox "github.com/gitlayzer/devbox-sdk-go"
+)
+
+var (
+	// Go122 is a Go 1.22 devbox serving on port 8080.
+	Go122 = devbox.Template{
+		Name:        "go-1.22",
+		Description: "Go 1.22 with an app on port 8080",
+		Config: devbox.DevboxConfig{
+			Image:      "ghcr.io/labring-actions/devbox/go-1.22:latest",
+			CPU:        2,
+			Memory:     4,
+			Ports:      []corev1.ContainerPort{{Name: "http", ContainerPort: 8080, Protocol: corev1.ProtocolTCP}},
+			WorkingDir: "/home/devbox/project",
+			User:       "devbox",
+		},
+	}
+
+	// Node20 is a Node.js 20 devbox serving on port 3000.
+	Node20 = devbox.Template{
+		Name:        "node-20",
+		Description: "Node.js 20 with an app on port 3000",
+		Config: devbox.DevboxConfig{
+			Image:      "ghcr.io/labring-actions/devbox/node.js-20:latest",
+			CPU:        2,
+			Memory:     4,
+			Ports:      []corev1.ContainerPort{{Name: "http", ContainerPort: 3000, Protocol: corev1.ProtocolTCP}},
+			WorkingDir: "/home/devbox/project",
+			User:       "devbox",
+		},
+	}
+
+	// Python312 is a Python 3.12 devbox serving on port 8000.
+	Python312 = devbox.Template{
+		Name:        "python-3.12",
+		Description: "Python 3.12 with an app on port 8000",
+		Config: devbox.DevboxConfig{
+			Image:      "ghcr.io/labring-actions/devbox/python-3.12:latest",
+			CPU:        2,
+			Memory:     4,
+			Ports:      []corev1.ContainerPort{{Name: "http", ContainerPort: 8000, Protocol: corev1.ProtocolTCP}},
+			WorkingDir: "/home/devbox/project",
+			User:       "devbox",
+			Env:        map[string]string{"PYTHONUNBUFFERED": "1"},
+		},
+	}
+)
+
+// All returns the templates of this package.
+func All() []devbox.Template {
+	return []devbox.Template{Go122, Node20, Python312}
+}
+
+func init() {
+	for _, t := range All() {
+		devbox.RegisterTemplate(t)
+	}
+}
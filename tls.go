@@ -0,0 +1,130 @@
+package devbox
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	annotationTLSSecretName = "devbox.sealos.run/tls-secret-name"
+	annotationTLSExpiresAt  = "devbox.sealos.run/tls-expires-at"
+)
+
+// GetTLSCertificate returns the certificate stored in the TLS Secret named by
+// the devbox's devbox.sealos.run/tls-secret-name annotation. The leaf
+// certificate is parsed into Leaf.
+func (d *Devbox) GetTLSCertificate(ctx context.Context) (*tls.Certificate, error) {
+	name, err := d.tlsSecretName()
+	if err != nil {
+		return nil, err
+	}
+	secret, err := d.sdk.client.Clientset().CoreV1().Secrets(d.crd.Namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return nil, fmt.Errorf("secret %s: %w", name, err)
+	}
+	if cert.Leaf == nil {
+		if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+			return nil, err
+		}
+	}
+	return &cert, nil
+}
+
+// SetTLSCertificate writes the certificate chain and its PEM-encoded private
+// key to the TLS Secret referenced by the devbox, creating it if needed.
+func (d *Devbox) SetTLSCertificate(ctx context.Context, cert *tls.Certificate, keyPEM []byte) error {
+	if cert == nil || len(cert.Certificate) == 0 {
+		return errors.New("certificate is empty")
+	}
+	var certPEM bytes.Buffer
+	for _, der := range cert.Certificate {
+		if err := pem.Encode(&certPEM, &pem.Block{Type: "CERTIFICATE", Bytes: der}); err != nil {
+			return err
+		}
+	}
+	if _, err := tls.X509KeyPair(certPEM.Bytes(), keyPEM); err != nil {
+		return err
+	}
+	return d.writeTLSSecret(ctx, certPEM.Bytes(), keyPEM)
+}
+
+// RotateTLSCertificate replaces the certificate in the TLS Secret referenced
+// by the devbox with the PEM-encoded certificate and key, and records the new
+// expiry in the devbox.sealos.run/tls-expires-at annotation.
+func (d *Devbox) RotateTLSCertificate(ctx context.Context, newCert, keyPEM []byte) error {
+	cert, err := tls.X509KeyPair(newCert, keyPEM)
+	if err != nil {
+		return err
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return err
+	}
+
+	if err := d.writeTLSSecret(ctx, newCert, keyPEM); err != nil {
+		return err
+	}
+	return d.patchAnnotations(ctx, map[string]interface{}{
+		annotationTLSExpiresAt: leaf.NotAfter.UTC().Format(time.RFC3339),
+	})
+}
+
+// writeTLSSecret creates or updates the TLS Secret referenced by the devbox.
+func (d *Devbox) writeTLSSecret(ctx context.Context, certPEM, keyPEM []byte) error {
+	name, err := d.tlsSecretName()
+	if err != nil {
+		return err
+	}
+	secrets := d.sdk.client.Clientset().CoreV1().Secrets(d.crd.Namespace)
+
+	secret, err := secrets.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = secrets.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: d.crd.Namespace,
+				Labels:    map[string]string{labelDevboxName: d.crd.Name},
+			},
+			Type: corev1.SecretTypeTLS,
+			Data: map[string][]byte{
+				corev1.TLSCertKey:       certPEM,
+				corev1.TLSPrivateKeyKey: keyPEM,
+			},
+		}, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[corev1.TLSCertKey] = certPEM
+	secret.Data[corev1.TLSPrivateKeyKey] = keyPEM
+	_, err = secrets.Update(ctx, secret, metav1.UpdateOptions{})
+	return err
+}
+
+func (d *Devbox) tlsSecretName() (string, error) {
+	name := d.crd.Annotations[annotationTLSSecretName]
+	if name == "" {
+		return "", fmt.Errorf("devbox %s has no %s annotation", d.crd.Name, annotationTLSSecretName)
+	}
+	return name, nil
+}
@@ -0,0 +1,86 @@
+package devbox
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation scope of the spans the SDK creates.
+const tracerName = "github.com/gitlayzer/devbox-sdk-go"
+
+// WithTracerProvider makes the SDK record OpenTelemetry spans with tp for
+// every Kubernetes request, wait loop, SSH connection and session, and
+// release creation. Spans on a devbox carry its name, namespace and phase.
+// The trace context is propagated to the API server in the request headers
+// with the global propagator, for clusters that trace requests themselves.
+func WithTracerProvider(tp trace.TracerProvider) DevboxSDKOption {
+	return func(s *DevboxSDK) {
+		s.tracer = tp.Tracer(tracerName)
+		s.wrapTransport(func(rt http.RoundTripper) http.RoundTripper {
+			return &tracingTransport{tracer: s.tracer, next: rt}
+		})
+	}
+}
+
+// startSpan starts a span with the tracer set with WithTracerProvider, or a
+// non-recording span if there is none.
+func (s *DevboxSDK) startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	tracer := s.tracer
+	if tracer == nil {
+		tracer = trace.NewNoopTracerProvider().Tracer(tracerName)
+	}
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// startSpan starts a span about the devbox.
+func (d *Devbox) startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs,
+		attribute.String("devbox.name", d.crd.Name),
+		attribute.String("devbox.namespace", d.crd.Namespace),
+		attribute.String("devbox.phase", string(d.crd.Status.Phase)),
+	)
+	return d.sdk.startSpan(ctx, name, attrs...)
+}
+
+// endSpan records err, if any, on span and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// tracingTransport records a client span for every request.
+type tracingTransport struct {
+	tracer trace.Tracer
+	next   http.RoundTripper
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := t.tracer.Start(req.Context(), "kubernetes "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("url.path", req.URL.Path),
+			attribute.String("server.address", req.URL.Host),
+		))
+	req = req.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := t.next.RoundTrip(req)
+	if err == nil {
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+		if resp.StatusCode >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, resp.Status)
+		}
+	}
+	endSpan(span, err)
+	return resp, err
+}
@@ -0,0 +1,153 @@
+package devbox
+
+import (
+	"context"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// UploadFile copies the local file localPath to remotePath on the devbox over
+// SFTP, creating missing parent directories and keeping the file mode.
+func (d *Devbox) UploadFile(ctx context.Context, localPath, remotePath string) error {
+	return d.withSFTP(ctx, func(session *sftpSession) error {
+		if err := session.MkdirAll(path.Dir(remotePath)); err != nil {
+			return err
+		}
+		return uploadFile(session, localPath, remotePath)
+	})
+}
+
+// DownloadFile copies remotePath on the devbox to the local file localPath
+// over SFTP, creating missing parent directories and keeping the file mode.
+func (d *Devbox) DownloadFile(ctx context.Context, remotePath, localPath string) error {
+	return d.withSFTP(ctx, func(session *sftpSession) error {
+		if err := os.MkdirAll(filepath.Dir(localPath), 0o755); err != nil {
+			return err
+		}
+		return downloadFile(session, remotePath, localPath)
+	})
+}
+
+// UploadDir copies the local directory localDir recursively to remoteDir on
+// the devbox. Existing files are overwritten; symbolic links and other
+// special files are skipped. For large trees, UploadTarball is faster.
+func (d *Devbox) UploadDir(ctx context.Context, localDir, remoteDir string) error {
+	return d.withSFTP(ctx, func(session *sftpSession) error {
+		return filepath.Walk(localDir, func(local string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(localDir, local)
+			if err != nil {
+				return err
+			}
+			remote := path.Join(remoteDir, filepath.ToSlash(rel))
+			switch {
+			case info.IsDir():
+				return session.MkdirAll(remote)
+			case info.Mode().IsRegular():
+				return uploadFile(session, local, remote)
+			default:
+				return nil
+			}
+		})
+	})
+}
+
+// DownloadDir copies remoteDir on the devbox recursively to the local
+// directory localDir. Existing files are overwritten; symbolic links and
+// other special files are skipped.
+func (d *Devbox) DownloadDir(ctx context.Context, remoteDir, localDir string) error {
+	return d.withSFTP(ctx, func(session *sftpSession) error {
+		walker := session.Walk(remoteDir)
+		for walker.Step() {
+			if err := walker.Err(); err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(remoteDir, walker.Path())
+			if err != nil {
+				return err
+			}
+			local := filepath.Join(localDir, filepath.FromSlash(rel))
+			info := walker.Stat()
+			switch {
+			case info.IsDir():
+				err = os.MkdirAll(local, 0o755)
+			case info.Mode().IsRegular():
+				err = downloadFile(session, walker.Path(), local)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// withSFTP runs fn with a new SFTP session, which is closed early if ctx is
+// cancelled.
+func (d *Devbox) withSFTP(ctx context.Context, fn func(*sftpSession) error) error {
+	session, err := d.sftpDial(ctx)
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	stop := context.AfterFunc(ctx, func() { session.Close() })
+	defer stop()
+
+	err = fn(session)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+func uploadFile(session *sftpSession, localPath, remotePath string) error {
+	src, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	dst, err := session.Create(remotePath)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	return session.Chmod(remotePath, info.Mode().Perm())
+}
+
+func downloadFile(session *sftpSession, remotePath, localPath string) error {
+	src, err := session.Open(remotePath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	dst, err := os.OpenFile(localPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := src.WriteTo(dst); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
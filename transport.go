@@ -0,0 +1,42 @@
+package devbox
+
+import (
+	"net/http"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/transport"
+)
+
+// TransportMiddleware wraps the round tripper the SDK sends Kubernetes
+// requests through, for instance to add headers, trace or log requests.
+type TransportMiddleware func(next http.RoundTripper) http.RoundTripper
+
+// WithTransportMiddleware adds middleware to the Kubernetes requests of the
+// SDK, including list and watch calls. The first middleware given sees each
+// request first. Middleware added by later options wraps earlier middleware.
+func WithTransportMiddleware(middleware ...TransportMiddleware) DevboxSDKOption {
+	return func(s *DevboxSDK) {
+		for i := len(middleware) - 1; i >= 0; i-- {
+			s.wrapTransport(middleware[i])
+		}
+	}
+}
+
+// WithRestConfig lets mutate change a copy of the rest config the SDK builds
+// its clients from, for instance to set a proxy, user agent, timeout or
+// impersonation.
+func WithRestConfig(mutate func(*rest.Config)) DevboxSDKOption {
+	return func(s *DevboxSDK) {
+		cfg := rest.CopyConfig(s.restConfig)
+		mutate(cfg)
+		s.restConfig = cfg
+	}
+}
+
+// wrapTransport points s at a copy of its rest config whose transport is
+// wrapped by mw.
+func (s *DevboxSDK) wrapTransport(mw TransportMiddleware) {
+	cfg := rest.CopyConfig(s.restConfig)
+	cfg.Wrap(transport.WrapperFunc(mw))
+	s.restConfig = cfg
+}
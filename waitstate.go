@@ -0,0 +1,88 @@
+package devbox
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/gitlayzer/devbox-sdk-go/api/v1alpha2"
+	"github.com/gitlayzer/devbox-sdk-go/types"
+)
+
+// DevboxStateDeleted is the pseudo-state WaitForState waits for when the
+// devbox should no longer exist.
+const DevboxStateDeleted v1alpha2.DevboxState = "Deleted"
+
+// stateSettled maps a desired state to the phase the devbox has once the
+// controller has finished moving it there.
+var stateSettled = map[v1alpha2.DevboxState]v1alpha2.DevboxPhase{
+	v1alpha2.DevboxStateRunning:  v1alpha2.DevboxPhaseRunning,
+	v1alpha2.DevboxStatePaused:   v1alpha2.DevboxPhasePaused,
+	v1alpha2.DevboxStateStopped:  v1alpha2.DevboxPhaseStopped,
+	v1alpha2.DevboxStateShutdown: v1alpha2.DevboxPhaseShutdown,
+}
+
+// WaitForState waits, using the backoff settings in opts, until the devbox
+// has settled in state: its phase matches and, for every state but Running,
+// all its pods, terminating ones included, are gone so that its compute
+// resources are released. For
+// DevboxStateDeleted it waits until the devbox no longer exists.
+func (d *Devbox) WaitForState(ctx context.Context, state v1alpha2.DevboxState, opts types.WaitForReadyOptions) error {
+	what := fmt.Sprintf("waiting for devbox to be %s", state)
+	if state == DevboxStateDeleted {
+		return d.sdk.pollWithBackoff(ctx, opts, what, func() (bool, error) {
+			err := d.RefreshInfo(ctx)
+			if apierrors.IsNotFound(err) {
+				return true, nil
+			}
+			return false, err
+		})
+	}
+
+	phase, ok := stateSettled[state]
+	if !ok {
+		return fmt.Errorf("unknown devbox state %q", state)
+	}
+	return d.sdk.pollWithBackoff(ctx, opts, what, func() (bool, error) {
+		if done, err := d.reachedPhase(ctx, phase); !done || err != nil {
+			return false, err
+		}
+		if state == v1alpha2.DevboxStateRunning {
+			return true, nil
+		}
+		// Terminating pods still hold their resources, so wait for every pod
+		// to be gone rather than just the live one getPod looks for.
+		pods, err := d.sdk.client.Clientset().CoreV1().Pods(d.crd.Namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: labels.SelectorFromSet(labels.Set{labelAppName: d.crd.Name}).String(),
+		})
+		if err != nil {
+			return false, apiError(err)
+		}
+		return len(pods.Items) == 0, nil
+	})
+}
+
+// WaitForPhase waits, using the backoff settings in opts, until the devbox
+// reports phase. Unlike WaitForState it does not wait for resources to be
+// released, and it can wait for transitional phases such as Stopping.
+func (d *Devbox) WaitForPhase(ctx context.Context, phase v1alpha2.DevboxPhase, opts types.WaitForReadyOptions) error {
+	return d.sdk.pollWithBackoff(ctx, opts, fmt.Sprintf("waiting for devbox phase %s", phase), func() (bool, error) {
+		return d.reachedPhase(ctx, phase)
+	})
+}
+
+// reachedPhase refreshes the devbox and reports whether it is in phase. It
+// fails if the devbox is in the Error phase instead.
+func (d *Devbox) reachedPhase(ctx context.Context, phase v1alpha2.DevboxPhase) (bool, error) {
+	if err := d.RefreshInfo(ctx); err != nil {
+		return false, err
+	}
+	current := d.crd.Status.Phase
+	if current == v1alpha2.DevboxPhaseError && phase != v1alpha2.DevboxPhaseError {
+		return false, fmt.Errorf("devbox %s: %w", d.crd.Name, ErrDevboxFailed)
+	}
+	return current == phase, nil
+}
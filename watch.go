@@ -0,0 +1,221 @@
+package devbox
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	watchtools "k8s.io/client-go/tools/watch"
+
+	"github.com/gitlayzer/devbox-sdk-go/api/v1alpha2"
+)
+
+// rewatchBackoff is how long watchResumable waits before listing again after
+// a watch could not be resumed or a list failed, doubling up to 30 seconds
+// while failures persist.
+var rewatchBackoff = wait.Backoff{
+	Duration: time.Second,
+	Factor:   2,
+	Jitter:   0.1,
+	Steps:    math.MaxInt32,
+	Cap:      30 * time.Second,
+}
+
+// WatchEventType is the kind of change a WatchEvent reports.
+type WatchEventType string
+
+const (
+	WatchAdded    WatchEventType = "Added"
+	WatchModified WatchEventType = "Modified"
+	WatchDeleted  WatchEventType = "Deleted"
+	// WatchError is the last event of a watch that failed for good, e.g.
+	// because the SDK lost permission to watch.
+	WatchError WatchEventType = "Error"
+)
+
+// WatchEvent is a change to a devbox. For WatchDeleted, Devbox is the last
+// known state of the deleted devbox. For WatchError, Devbox is nil and Err
+// says why the watch ended.
+type WatchEvent struct {
+	Type   WatchEventType
+	Devbox *Devbox
+	Err    error
+}
+
+// WatchOptions restricts the devboxes WatchDevboxes reports on. Empty
+// selectors match every devbox in the namespace.
+type WatchOptions struct {
+	LabelSelector string
+	FieldSelector string
+}
+
+// WatchDevboxes streams changes to the devboxes in the SDK namespace, keeping
+// the SDK cache up to date. Existing devboxes are not replayed, so list them
+// first if the initial state is needed. The watch is resumed
+// after server-side timeouts and transient failures, and the channel is
+// closed when ctx is cancelled or after a WatchError event.
+func (s *DevboxSDK) WatchDevboxes(ctx context.Context, opts WatchOptions) (<-chan WatchEvent, error) {
+	raw, err := watchResumable(ctx, &cache.ListWatch{
+		ListFunc: func(o metav1.ListOptions) (runtime.Object, error) {
+			o.LabelSelector, o.FieldSelector = opts.LabelSelector, opts.FieldSelector
+			return s.client.List(ctx, o)
+		},
+		WatchFunc: func(o metav1.ListOptions) (watch.Interface, error) {
+			o.LabelSelector, o.FieldSelector = opts.LabelSelector, opts.FieldSelector
+			return s.client.Watch(ctx, o)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan WatchEvent)
+	go func() {
+		defer close(out)
+		for e := range raw {
+			if e.Type == watch.Error {
+				select {
+				case out <- WatchEvent{Type: WatchError, Err: apiError(apierrors.FromObject(e.Object))}:
+				case <-ctx.Done():
+				}
+				return
+			}
+			crd, ok := e.Object.(*v1alpha2.Devbox)
+			if !ok {
+				continue
+			}
+			event := WatchEvent{Devbox: newDevbox(crd, s)}
+			switch e.Type {
+			case watch.Added:
+				event.Type = WatchAdded
+				s.cache.Set(crd.Name, crd)
+			case watch.Modified:
+				event.Type = WatchModified
+				s.cache.Set(crd.Name, crd)
+			case watch.Deleted:
+				event.Type = WatchDeleted
+				s.cache.Delete(crd.Name)
+			default:
+				continue
+			}
+			select {
+			case out <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// watchResumable lists with lw and then watches from the returned resource
+// version. Server-side timeouts are resumed transparently; if the resource
+// version has expired or the watch fails, the watch starts over from a fresh
+// list, backing off while lists fail. An error that retrying cannot fix,
+// such as a lost permission, is delivered as a final watch.Error event whose
+// object is the API status. The returned channel is closed after that event
+// or when ctx is done.
+func watchResumable(ctx context.Context, lw *cache.ListWatch) (<-chan watch.Event, error) {
+	resourceVersion, err := listResourceVersion(lw)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan watch.Event)
+	go func() {
+		defer close(events)
+		backoff := rewatchBackoff
+		for {
+			w, err := watchtools.NewRetryWatcher(resourceVersion, lw)
+			if err == nil {
+				err = forwardEvents(ctx, w, events)
+				w.Stop()
+			}
+
+			for err == nil || !terminalWatchError(err) {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(backoff.Step()):
+				}
+				if resourceVersion, err = listResourceVersion(lw); err == nil {
+					backoff = rewatchBackoff
+					break
+				}
+			}
+			if err != nil {
+				select {
+				case events <- watch.Event{Type: watch.Error, Object: watchErrorStatus(err)}:
+				case <-ctx.Done():
+				}
+				return
+			}
+		}
+	}()
+	return events, nil
+}
+
+// forwardEvents copies events from w to out until w ends or ctx is done. It
+// returns the error of the error event that ended w, if any.
+func forwardEvents(ctx context.Context, w watch.Interface, out chan<- watch.Event) error {
+	var lastErr error
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				return lastErr
+			}
+			if event.Type == watch.Error {
+				// The retry watcher ends after an error it cannot retry;
+				// let the caller decide whether to start over.
+				lastErr = apierrors.FromObject(event.Object)
+				continue
+			}
+			lastErr = nil
+			select {
+			case out <- event:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}
+}
+
+// terminalWatchError reports whether err means that listing or watching will
+// keep failing however often it is retried.
+func terminalWatchError(err error) bool {
+	return apierrors.IsForbidden(err) || apierrors.IsUnauthorized(err) || apierrors.IsNotFound(err) ||
+		apierrors.IsBadRequest(err) || apierrors.IsInvalid(err) || apierrors.IsMethodNotSupported(err)
+}
+
+// watchErrorStatus returns the API status of err for a watch.Error event.
+func watchErrorStatus(err error) *metav1.Status {
+	var status apierrors.APIStatus
+	if errors.As(err, &status) {
+		s := status.Status()
+		return &s
+	}
+	return &metav1.Status{Status: metav1.StatusFailure, Message: err.Error()}
+}
+
+func listResourceVersion(lw *cache.ListWatch) (string, error) {
+	list, err := lw.List(metav1.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+	accessor, err := meta.ListAccessor(list)
+	if err != nil {
+		return "", err
+	}
+	return accessor.GetResourceVersion(), nil
+}